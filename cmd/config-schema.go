@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fikaworks/grgate/pkg/config"
+)
+
+// configSchemaCmd represents the config schema command
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema [main|repo]",
+	Short: "Print the JSON Schema of the main or repository config file",
+	Long: `Example:
+  # print the JSON Schema of the .grgate.yaml file
+  grgate config schema repo
+
+  # print the JSON Schema of the main config file
+  grgate config schema main`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires at least one arg")
+		}
+		if args[0] != "main" && args[0] != "repo" {
+			return fmt.Errorf("unsupported schema \"%s\", must be one of: main, repo",
+				args[0])
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var schema []byte
+		if args[0] == "main" {
+			schema, err = config.MainConfigJSONSchema()
+		} else {
+			schema, err = config.RepoConfigJSONSchema()
+		}
+		if err != nil {
+			return
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(schema))
+
+		return
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSchemaCmd)
+}
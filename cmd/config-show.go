@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+type configShowFlagsStruct struct {
+	output string
+	ref    string
+}
+
+var configShowFlags configShowFlagsStruct
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show [URL OR REPO/OWNER]",
+	Short: "Show the effective config of a repository",
+	Long: `The show command print the config of a repository once the defaults,
+the main config file, flags, the organization config file and the repository
+config file are merged. Each setting is annotated with its source.
+
+Example:
+  # show the effective config of a repository as YAML
+  grgate config show my-org/my-repo
+
+  # show the effective config of a repository as JSON
+  grgate config show my-org/my-repo -o json
+
+  # show the effective config of a maintenance branch
+  grgate config show my-org/my-repo --ref release-1.x`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires at least one arg")
+		}
+		if _, err := utils.ExtractRepository(args[0]); err != nil {
+			return err
+		}
+		if configShowFlags.output != "yaml" && configShowFlags.output != "json" {
+			return fmt.Errorf("unsupported output \"%s\", must be one of: yaml, json",
+				configShowFlags.output)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		platform, err := newPlatform()
+		if err != nil {
+			return
+		}
+
+		repository, err := utils.ExtractRepository(args[0])
+		if err != nil {
+			return err
+		}
+
+		loader := config.NewLoader(mainConfig, log.Logger)
+
+		var repoConfig *config.RepoConfig
+		if configShowFlags.ref != "" {
+			repoConfig, err = loader.RepoConfigAtRef(platform, repository.Owner,
+				repository.Name, configShowFlags.ref)
+		} else {
+			repoConfig, err = loader.RepoConfig(platform, repository.Owner,
+				repository.Name)
+		}
+		if err != nil {
+			return
+		}
+
+		for _, e := range repoConfig.Errors {
+			log.Warn().Msg(e)
+		}
+
+		if configShowFlags.output == "json" {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(repoConfig.Settings())
+		}
+
+		node, err := repoConfig.YAMLNode()
+		if err != nil {
+			return
+		}
+
+		encoder := yaml.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent(2)
+		if err = encoder.Encode(node); err != nil {
+			return
+		}
+
+		return encoder.Close()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+
+	flags := configShowCmd.Flags()
+
+	flags.StringVarP(&configShowFlags.output, "output", "o", "yaml",
+		"output format: yaml or json")
+	flags.StringVar(&configShowFlags.ref, "ref", "",
+		"branch, tag or commit to read the repository config file from")
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+type configValidateFlagsStruct struct {
+	main bool
+	ref  string
+}
+
+var configValidateFlags configValidateFlagsStruct
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [FILE OR URL OR REPO/OWNER]",
+	Short: "Validate a repository config file or the main config file",
+	Long: `The validate command report unknown keys, values of the wrong type,
+invalid regexps and templates found in a config file. The argument is either a
+local file or a repository, in which case the first repository config file
+found is read from the default branch or from the ref defined by the
+repoConfigRef setting. The format of the file (YAML, JSON or TOML) is detected
+from its extension.
+
+Example:
+  # validate a local .grgate.yaml file
+  grgate config validate .grgate.yaml
+
+  # validate the .grgate.yaml file of a repository
+  grgate config validate my-org/my-repo
+
+  # validate the .grgate.yaml file of a maintenance branch
+  grgate config validate my-org/my-repo --ref release-1.x
+
+  # validate the main config file
+  grgate config validate --main /etc/grgate/config.yaml`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires at least one arg")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		name := args[0]
+
+		var content []byte
+		format := config.ConfigFormat(name)
+		if _, statErr := os.Stat(name); statErr == nil {
+			if content, err = os.ReadFile(name); err != nil {
+				return
+			}
+		} else {
+			if configValidateFlags.main {
+				return statErr
+			}
+
+			repository, err := utils.ExtractRepository(name)
+			if err != nil {
+				return err
+			}
+
+			platform, err := newPlatform()
+			if err != nil {
+				return err
+			}
+
+			ref := configValidateFlags.ref
+			if ref == "" && mainConfig.RepoConfigMode == config.RepoConfigModeRef {
+				ref = mainConfig.RepoConfigRef
+			}
+
+			var path string
+			path, content, err = config.NewLoader(mainConfig, log.Logger).
+				ReadRepoConfigFile(platform, repository.Owner, repository.Name, ref)
+			if err != nil {
+				return err
+			}
+
+			format = config.ConfigFormat(path)
+			name = fmt.Sprintf("%s/%s/%s", repository.Owner, repository.Name, path)
+		}
+
+		var validationErrors []*config.ValidationError
+		if configValidateFlags.main {
+			validationErrors = config.ValidateMainConfig(content, format)
+		} else {
+			validationErrors = config.ValidateRepoConfig(content, format)
+		}
+
+		for _, e := range validationErrors {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", name, e)
+		}
+
+		if len(validationErrors) > 0 {
+			// errors are already reported, the usage is not relevant
+			cmd.SilenceUsage = true
+			return fmt.Errorf("found %d error(s) in %s", len(validationErrors), name)
+		}
+
+		log.Info().Msgf("%s is valid", name)
+
+		return
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+
+	flags := configValidateCmd.Flags()
+
+	flags.BoolVar(&configValidateFlags.main, "main", false,
+		"validate the file as the main config instead of a repository config")
+	flags.StringVar(&configValidateFlags.ref, "ref", "",
+		"branch, tag or commit to read the repository config file from")
+}
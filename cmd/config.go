@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config [COMMANDS]",
+	Short: "Validate and inspect GRGate config",
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
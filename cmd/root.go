@@ -13,6 +13,13 @@ import (
 
 var (
 	cfgFile string
+
+	// cfgFileUsed is the config file found when loading the config, empty
+	// if the default settings are used
+	cfgFileUsed string
+
+	// mainConfig is the main config loaded when the command is initialized
+	mainConfig *config.MainConfig
 )
 
 // rootCmd represents the root command
@@ -21,7 +28,12 @@ var rootCmd = &cobra.Command{
 	Short: "Publish draft/unpublished releases if all status check succeed",
 	Long: `GRGate is a git release gate utility which autopublish
 draft/unpublished releases based on commit status (aka checks). It can be
-triggered automatically using Git webhook or directly from the CLI.`,
+triggered automatically using Git webhook or directly from the CLI.
+
+Every setting of the config file can be overridden by an environment variable
+named after its key, ie: GRGATE_GITLAB_TOKEN for gitlab.token or
+GRGATE_GLOBALS_TAGREGEXP for globals.tagRegexp. Secrets can be read from
+files with gitlab.tokenFile, server.webhookSecretFile and externalSecretFile.`,
 }
 
 func init() {
@@ -45,27 +57,18 @@ func init() {
 
 func initConfig() {
 	// read global config and override it with flags value
-	globalConfig, err := config.NewGlobalConfig(cfgFile)
+	globalConfig, loadedConfig, err := config.LoadGlobalConfig(cfgFile,
+		rootCmd.PersistentFlags())
 	if err != nil {
 		fmt.Print(err)
 		os.Exit(1)
 		return
 	}
 
-	if err := globalConfig.BindPFlags(rootCmd.PersistentFlags()); err != nil {
-		fmt.Print(err)
-		os.Exit(1)
-		return
-	}
-
-	if err := globalConfig.Unmarshal(&config.Main); err != nil {
-		fmt.Print(err)
-		os.Exit(1)
-		return
-	}
+	mainConfig = loadedConfig
 
 	// logs
-	logLevel, err := zerolog.ParseLevel(config.Main.LogLevel)
+	logLevel, err := zerolog.ParseLevel(mainConfig.LogLevel)
 	if err != nil {
 		log.Error().Err(err)
 		return
@@ -73,7 +76,7 @@ func initConfig() {
 
 	zerolog.SetGlobalLevel(logLevel)
 
-	if config.Main.LogFormat == "pretty" {
+	if mainConfig.LogFormat == "pretty" {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
@@ -82,9 +85,9 @@ func initConfig() {
 		Logger()
 
 	// inform about which config file is being used
-	configFile := globalConfig.ConfigFileUsed()
-	if configFile != "" {
-		log.Info().Msgf("Using config file: %s", configFile)
+	cfgFileUsed = globalConfig.ConfigFileUsed()
+	if cfgFileUsed != "" {
+		log.Info().Msgf("Using config file: %s", cfgFileUsed)
 	}
 }
 
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -11,9 +12,10 @@ import (
 )
 
 type runCmdFlagsStruct struct {
-	dryRun    bool
-	tagRegexp string
-	statuses  []string
+	dryRun       bool
+	tagRegexp    string
+	statuses     []string
+	statusesFrom string
 }
 
 var runCmdFlags runCmdFlagsStruct
@@ -58,7 +60,20 @@ Example:
 			return
 		}
 
-		job, err := workers.NewJob(platform, repository.Owner, repository.Name)
+		engine, err := workers.NewEngine(workers.Options{
+			Platform: platform,
+			Config:   mainConfig,
+		})
+		if err != nil {
+			return
+		}
+
+		if !engine.IsRepositoryAllowed(repository.Owner, repository.Name) {
+			return fmt.Errorf("repository %s/%s is not allowed by the repositories settings",
+				repository.Owner, repository.Name)
+		}
+
+		job, err := engine.NewJob(repository.Owner, repository.Name)
 		if err != nil {
 			return err
 		}
@@ -67,6 +82,9 @@ Example:
 		if len(runCmdFlags.statuses) > 0 {
 			job.Config.Statuses = runCmdFlags.statuses
 		}
+		if runCmdFlags.statusesFrom != "" {
+			job.Config.StatusesFrom = runCmdFlags.statusesFrom
+		}
 		job.Config.TagRegexp = runCmdFlags.tagRegexp
 		job.Config.Enabled = !runCmdFlags.dryRun
 
@@ -84,4 +102,6 @@ func init() {
 	flags.StringVar(&runCmdFlags.tagRegexp, "tag-regexp", ".*", "tag regexp")
 	flags.StringArrayVarP(&runCmdFlags.statuses, "status", "s", []string{},
 		"List of status to succeed")
+	flags.StringVar(&runCmdFlags.statusesFrom, "statuses-from", "",
+		"Discover required statuses from the platform, ie: branchProtection")
 }
@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/fikaworks/grgate/pkg/config"
 	"github.com/fikaworks/grgate/pkg/server"
+	"github.com/fikaworks/grgate/pkg/workers"
 )
 
 // serveCmd represents the serve command
@@ -17,6 +20,9 @@ functionalities:
   - 0.0.0.0:8080 listen for git webhook
   - 0.0.0.0:9101 expose Prometheus metrics
   - 0.0.0.0:8086 expose health probe (liveness/readiness)
+
+The config is reloaded when the config file changes or when the process
+receives SIGHUP. An invalid config is rejected and the current config kept.
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		platform, err := newPlatform()
@@ -24,21 +30,69 @@ functionalities:
 			return
 		}
 
-		srv := server.NewServer(&server.Config{
-			ListenAddr:    config.Main.Server.ListenAddress,
-			Logger:        log.Logger,
-			MetricsAddr:   config.Main.Server.MetricsAddress,
-			Platform:      platform,
-			ProbeAddr:     config.Main.Server.ProbeAddress,
-			WebhookSecret: config.Main.Server.WebhookSecret,
-			Workers:       config.Main.Workers,
+		engine, err := workers.NewEngine(workers.Options{
+			Platform:   platform,
+			Config:     mainConfig,
+			Registerer: prometheus.DefaultRegisterer,
 		})
+		if err != nil {
+			return
+		}
+
+		serverConfig := newServerConfig(mainConfig, engine)
+		serverConfig.ConfigFile = cfgFileUsed
+		serverConfig.Reload = func() (*server.Config, error) {
+			return reloadServerConfig(engine)
+		}
+
+		srv := server.NewServer(serverConfig)
 		srv.Start()
 
 		return
 	},
 }
 
+// newServerConfig returns the server config from the main config
+func newServerConfig(mainConfig *config.MainConfig,
+	engine *workers.Engine) *server.Config {
+	logLevel, _ := zerolog.ParseLevel(mainConfig.LogLevel)
+
+	return &server.Config{
+		Engine:        engine,
+		ListenAddr:    mainConfig.Server.ListenAddress,
+		LogFormat:     mainConfig.LogFormat,
+		LogLevel:      logLevel,
+		Logger:        log.Logger,
+		MetricsAddr:   mainConfig.Server.MetricsAddress,
+		ProbeAddr:     mainConfig.Server.ProbeAddress,
+		WebhookSecret: mainConfig.Server.WebhookSecret,
+		Workers:       mainConfig.Workers,
+	}
+}
+
+// reloadServerConfig read and validate the main config, the engine is only
+// updated if the config is valid and the platform client could be created
+func reloadServerConfig(engine *workers.Engine) (*server.Config, error) {
+	_, mainConfig, err := config.LoadGlobalConfig(cfgFile,
+		rootCmd.PersistentFlags())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := zerolog.ParseLevel(mainConfig.LogLevel); err != nil {
+		return nil, err
+	}
+
+	platform, err := newPlatformFromConfig(mainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	engine.Update(platform, mainConfig)
+
+	return newServerConfig(mainConfig, engine), nil
+}
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
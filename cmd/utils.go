@@ -8,19 +8,26 @@ import (
 )
 
 func newPlatform() (platform platforms.Platform, err error) {
-	switch *config.Main.Platform {
+	return newPlatformFromConfig(mainConfig)
+}
+
+// newPlatformFromConfig returns a platform client configured from the given
+// main config
+func newPlatformFromConfig(mainConfig *config.MainConfig) (platform platforms.Platform, err error) {
+	switch *mainConfig.Platform {
 	case config.GitlabPlatform:
 		platform, err = platforms.NewGitlab(&platforms.GitlabConfig{
-			Token: config.Main.Gitlab.Token,
+			Token: mainConfig.Gitlab.Token,
 		})
 	case config.GithubPlatform:
 		platform, err = platforms.NewGithub(&platforms.GithubConfig{
-			AppID:          config.Main.Github.AppID,
-			InstallationID: config.Main.Github.InstallationID,
-			PrivateKeyPath: config.Main.Github.PrivateKeyPath,
+			AppID:          mainConfig.Github.AppID,
+			InstallationID: mainConfig.Github.InstallationID,
+			PrivateKey:     []byte(mainConfig.Github.PrivateKey),
+			PrivateKeyPath: mainConfig.Github.PrivateKeyPath,
 		})
 	default:
-		err = fmt.Errorf("platform %s is not recognized", *config.Main.Platform)
+		err = fmt.Errorf("platform %s is not recognized", *mainConfig.Platform)
 	}
 	return
 }
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+// cachedFile is a config file read from a repository, err is set if the file
+// doesn't exist
+type cachedFile struct {
+	content   []byte
+	err       error
+	expiresAt time.Time
+}
+
+// fileCache keep the config files read from repositories, indexed by
+// "owner/repository/path" or "owner/repository/path@ref" if the file isn't
+// read from the default branch. Missing files are cached as well, other
+// platform errors are not cached so that they don't replace the repository
+// config by the default settings.
+//
+// The key deliberately doesn't include the blob SHA of the file: checking the
+// SHA would cost an API call per read, which is what the cache avoids. Entries
+// are only removed when a push event lists the file as changed or once they
+// expire, the TTL is the only safety net for changes which are not listed by
+// a push event, ie: a force-push, a branch reset or a missed webhook. Such
+// changes are picked up after at most repoConfigCacheTTL
+type fileCache struct {
+	sync.Mutex
+	files map[string]*cachedFile
+}
+
+// newFileCache returns an empty file cache
+func newFileCache() *fileCache {
+	return &fileCache{files: make(map[string]*cachedFile)}
+}
+
+// fileCacheKey returns the key of a file in the cache
+func fileCacheKey(owner, repository, path, ref string) string {
+	key := owner + "/" + repository + "/" + path
+	if ref != "" {
+		key += "@" + ref
+	}
+	return key
+}
+
+// readCachedFile returns the content of a file at ref from the cache or from
+// the platform if the cached file expired. Files are not cached if the
+// repoConfigCacheTTL setting is 0
+func (l *Loader) readCachedFile(platform platforms.Platform, owner, repository,
+	path, ref string) (content []byte, err error) {
+	key := fileCacheKey(owner, repository, path, ref)
+	ttl := l.config.RepoConfigCacheTTL
+
+	if ttl > 0 {
+		l.cache.Lock()
+		file, ok := l.cache.files[key]
+		l.cache.Unlock()
+
+		if ok && time.Now().Before(file.expiresAt) {
+			l.logger.Debug().
+				Str("owner", owner).
+				Str("repository", repository).
+				Msgf("Using cached file \"%s\"", path)
+			return file.content, file.err
+		}
+	}
+
+	reader, err := platform.ReadFile(owner, repository, path, ref)
+	if err != nil {
+		if ttl > 0 && errors.Is(err, platforms.ErrFileNotFound) {
+			l.cache.Lock()
+			l.cache.files[key] = &cachedFile{
+				err:       err,
+				expiresAt: time.Now().Add(ttl),
+			}
+			l.cache.Unlock()
+		}
+		return
+	}
+
+	if content, err = io.ReadAll(reader); err != nil {
+		return
+	}
+
+	if ttl > 0 {
+		l.cache.Lock()
+		l.cache.files[key] = &cachedFile{
+			content:   content,
+			expiresAt: time.Now().Add(ttl),
+		}
+		l.cache.Unlock()
+	}
+
+	return content, nil
+}
+
+// InvalidateRepoConfigCache remove the cached config files of a repository
+// read at ref which are listed in paths, an empty ref is the default branch.
+// All the cached files of the repository are removed if no path is provided
+func (l *Loader) InvalidateRepoConfigCache(owner, repository, ref string, paths ...string) {
+	l.cache.Lock()
+	defer l.cache.Unlock()
+
+	if len(paths) == 0 {
+		prefix := fileCacheKey(owner, repository, "", "")
+		for key := range l.cache.files {
+			if strings.HasPrefix(key, prefix) {
+				delete(l.cache.files, key)
+			}
+		}
+		return
+	}
+
+	for _, path := range paths {
+		key := fileCacheKey(owner, repository, path, ref)
+		if _, ok := l.cache.files[key]; ok {
+			l.logger.Debug().
+				Str("owner", owner).
+				Str("repository", repository).
+				Msgf("File \"%s\" changed, removing it from the cache", path)
+			delete(l.cache.files, key)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+//go:build unit
+
+package config
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestReadCachedFile(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	readFile := func(content string) func(string, string, string, string) (io.Reader, error) {
+		return func(_, _, _, _ string) (io.Reader, error) {
+			return strings.NewReader(content), nil
+		}
+	}
+
+	t.Run("should cache files until they are invalidated", func(t *testing.T) {
+		loader := NewLoader(&MainConfig{RepoConfigCacheTTL: time.Minute}, zerolog.Nop())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		gomock.InOrder(
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+				DoAndReturn(readFile("enabled: true")),
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+				DoAndReturn(readFile("enabled: false")),
+		)
+
+		for _, expected := range []string{"enabled: true", "enabled: true"} {
+			content, err := loader.readCachedFile(mockPlatforms, "owner", "repository",
+				".grgate.yaml", "")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected %#v, got %#v", expected, string(content))
+			}
+		}
+
+		loader.InvalidateRepoConfigCache("owner", "repository", "", "README.md")
+
+		content, _ := loader.readCachedFile(mockPlatforms, "owner", "repository",
+			".grgate.yaml", "")
+		if string(content) != "enabled: true" {
+			t.Errorf("Expected file to stay cached when other files change")
+		}
+
+		loader.InvalidateRepoConfigCache("owner", "repository", "", ".grgate.yaml")
+
+		content, _ = loader.readCachedFile(mockPlatforms, "owner", "repository",
+			".grgate.yaml", "")
+		if string(content) != "enabled: false" {
+			t.Errorf("Expected file to be read again once invalidated, got %#v",
+				string(content))
+		}
+	})
+
+	t.Run("should read files again once expired", func(t *testing.T) {
+		loader := NewLoader(&MainConfig{RepoConfigCacheTTL: time.Minute}, zerolog.Nop())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+			DoAndReturn(readFile("enabled: true")).
+			Times(2)
+
+		if _, err := loader.readCachedFile(mockPlatforms, "owner", "repository",
+			".grgate.yaml", ""); err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+
+		loader.cache.Lock()
+		loader.cache.files[fileCacheKey("owner", "repository", ".grgate.yaml", "")].expiresAt =
+			time.Now().Add(-time.Second)
+		loader.cache.Unlock()
+
+		if _, err := loader.readCachedFile(mockPlatforms, "owner", "repository",
+			".grgate.yaml", ""); err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+	})
+
+	t.Run("should cache missing files until they are invalidated", func(t *testing.T) {
+		loader := NewLoader(&MainConfig{RepoConfigCacheTTL: time.Minute}, zerolog.Nop())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		gomock.InOrder(
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+				Return(nil, platforms.ErrFileNotFound),
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+				DoAndReturn(readFile("enabled: true")),
+		)
+
+		for i := 0; i < 2; i++ {
+			if _, err := loader.readCachedFile(mockPlatforms, "owner", "repository",
+				".grgate.yaml", ""); !errors.Is(err, platforms.ErrFileNotFound) {
+				t.Errorf("Expected file not found error, got %#v", err)
+			}
+		}
+
+		loader.InvalidateRepoConfigCache("owner", "repository", "", ".grgate.yaml")
+
+		content, _ := loader.readCachedFile(mockPlatforms, "owner", "repository",
+			".grgate.yaml", "")
+		if string(content) != "enabled: true" {
+			t.Errorf("Expected file to be read again once invalidated, got %#v",
+				string(content))
+		}
+	})
+
+	t.Run("should not cache platform errors", func(t *testing.T) {
+		loader := NewLoader(&MainConfig{RepoConfigCacheTTL: time.Minute}, zerolog.Nop())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+			Return(nil, errors.New("bad gateway")).
+			Times(2)
+
+		for i := 0; i < 2; i++ {
+			if _, err := loader.readCachedFile(mockPlatforms, "owner", "repository",
+				".grgate.yaml", ""); err == nil {
+				t.Errorf("Expected error")
+			}
+		}
+	})
+
+	t.Run("should invalidate all the files of a repository", func(t *testing.T) {
+		loader := NewLoader(&MainConfig{RepoConfigCacheTTL: time.Minute}, zerolog.Nop())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		mockPlatforms.EXPECT().ReadFile(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(readFile("enabled: true")).
+			Times(3)
+
+		for _, repository := range []string{"repository", "repository-2"} {
+			if _, err := loader.readCachedFile(mockPlatforms, "owner", repository,
+				".grgate.yaml", ""); err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+		}
+
+		loader.InvalidateRepoConfigCache("owner", "repository", "")
+
+		for _, repository := range []string{"repository", "repository-2"} {
+			if _, err := loader.readCachedFile(mockPlatforms, "owner", repository,
+				".grgate.yaml", ""); err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+		}
+	})
+
+	t.Run("should cache files per ref", func(t *testing.T) {
+		loader := NewLoader(&MainConfig{RepoConfigCacheTTL: time.Minute}, zerolog.Nop())
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		gomock.InOrder(
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+				DoAndReturn(readFile("enabled: true")),
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "v1").
+				DoAndReturn(readFile("enabled: false")),
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "v1").
+				DoAndReturn(readFile("enabled: true")),
+		)
+
+		for _, test := range []struct {
+			ref      string
+			expected string
+		}{
+			{"", "enabled: true"},
+			{"v1", "enabled: false"},
+			{"", "enabled: true"},
+			{"v1", "enabled: false"},
+		} {
+			content, _ := loader.readCachedFile(mockPlatforms, "owner", "repository",
+				".grgate.yaml", test.ref)
+			if string(content) != test.expected {
+				t.Errorf("Expected %#v at ref %#v, got %#v", test.expected, test.ref,
+					string(content))
+			}
+		}
+
+		loader.InvalidateRepoConfigCache("owner", "repository", "", ".grgate.yaml")
+
+		content, _ := loader.readCachedFile(mockPlatforms, "owner", "repository",
+			".grgate.yaml", "v1")
+		if string(content) != "enabled: false" {
+			t.Errorf("Expected file to stay cached when the default branch changes")
+		}
+
+		loader.InvalidateRepoConfigCache("owner", "repository", "v1", ".grgate.yaml")
+
+		content, _ = loader.readCachedFile(mockPlatforms, "owner", "repository",
+			".grgate.yaml", "v1")
+		if string(content) != "enabled: true" {
+			t.Errorf("Expected file to be read again once invalidated, got %#v",
+				string(content))
+		}
+	})
+}
@@ -1,12 +1,11 @@
 package config
 
+import "time"
+
 var (
 	// CommitSha from source repository used to build GRGate
 	CommitSha string
 
-	// Main contains the generated main configuration
-	Main *MainConfig
-
 	// Version of GRGate
 	Version string
 )
@@ -19,6 +18,25 @@ const (
 	// the repository or not
 	DefaultEnabled bool = true
 
+	// DefaultAssetsEnabled define if releases should only be published when
+	// the required assets are attached to it
+	DefaultAssetsEnabled bool = false
+
+	// DefaultBlockersEnabled define if releases should be held while blocker
+	// issues are open
+	DefaultBlockersEnabled bool = false
+
+	// DefaultBlockersLabel is the default label of blocker issues
+	DefaultBlockersLabel string = "release-blocker"
+
+	// DefaultBlockersMilestone define if open issues attached to the milestone
+	// named after the release tag are blockers
+	DefaultBlockersMilestone bool = true
+
+	// DefaultBlockersPullRequests define if pull requests (Github) or merge
+	// requests (Gitlab) are also considered as blockers
+	DefaultBlockersPullRequests bool = false
+
 	// DefaultDashboardEnabled define if the issue dashboard your be enabled to
 	// provide feedback on the state of GRGate
 	DefaultDashboardEnabled bool = true
@@ -41,8 +59,76 @@ Incorrect configuration detected with the following error(s):
 {{- end }}
 {{- end }}
 
+{{- if .BlockedReleases }}
+
+Release(s) blocked by a gate:
+{{- range .BlockedReleases }}
+- {{ .Tag }}: {{ .Reason }}
+{{- end }}
+{{- end }}
+
+{{- if .QueuedReleases }}
+
+Release(s) queued until the publish quota allow them:
+{{- range .QueuedReleases }}
+- {{ .Tag }}: {{ .Reason }}
+{{- end }}
+{{- end }}
+
+{{- if .RetriedStatuses }}
+
+Failed status(es) automatically retried:
+{{- range .RetriedStatuses }}
+- {{ .Tag }}: {{ .Status }} retried {{ .Attempts }}/{{ .Retries }} time(s)
+{{- end }}
+{{- end }}
+
+{{- if .TimedOutReleases }}
+
+Release(s) blocked after exceeding the timeout:
+{{- range .TimedOutReleases }}
+- {{ .Tag }}: {{ .Reason }}
+{{- end }}
+{{- end }}
+
+{{- if .RolledBackReleases }}
+
+Release(s) reverted to draft after a required status failed:
+{{- range .RolledBackReleases }}
+- {{ .Tag }}: {{ .Reason }}
+{{- end }}
+{{- end }}
+
 Last time GRGate processed this repository: {{ .LastExecutionTime }}`
 
+	// DefaultSignatureEnabled define if releases should only be published when
+	// their tag or commit has a verified signature
+	DefaultSignatureEnabled bool = false
+
+	// DefaultSignatureTarget define which git object signature is verified,
+	// either tag or commit
+	DefaultSignatureTarget string = "tag"
+
+	// StatusesFromBranchProtection define that required statuses are
+	// discovered from the branch protection rules of the repository
+	StatusesFromBranchProtection string = "branchProtection"
+
+	// DefaultTimeoutDuration define after how long a draft release which
+	// required statuses didn't succeed is marked as blocked, 0 disable it
+	DefaultTimeoutDuration time.Duration = 0
+
+	// DefaultTimeoutRetention define for how long a blocked draft release is
+	// kept before being deleted, 0 disable the deletion
+	DefaultTimeoutRetention time.Duration = 0
+
+	// DefaultTimeoutMarkerStart is the string that define the start of the
+	// timeout notice added to the release note
+	DefaultTimeoutMarkerStart string = "<!-- GRGate timeout start -->"
+
+	// DefaultTimeoutMarkerEnd is the string that define the end of the
+	// timeout notice added to the release note
+	DefaultTimeoutMarkerEnd string = "<!-- GRGate timeout end -->"
+
 	// DefaultTagRegexp is the default pattern used to match tags attached to
 	// releases
 	DefaultTagRegexp string = ".*"
@@ -50,6 +136,47 @@ Last time GRGate processed this repository: {{ .LastExecutionTime }}`
 	// DefaultPlatform is the default platform
 	DefaultPlatform PlatformType = GithubPlatform
 
+	// DefaultExternalEnabled define if releases should only be published when
+	// approved by an external decision service
+	DefaultExternalEnabled bool = false
+
+	// DefaultDependsOnRecheckInterval define how long to wait before checking
+	// the dependencies again when an upstream release is missing. Dependents
+	// are also processed when an upstream release is published
+	DefaultDependsOnRecheckInterval time.Duration = 5 * time.Minute
+
+	// DefaultExternalRecheckInterval define how long to wait before asking
+	// the external decision service again when the decision is pending
+	DefaultExternalRecheckInterval time.Duration = 5 * time.Minute
+
+	// DefaultMetricsEnabled define if releases should only be published when
+	// the metric queries satisfy their threshold
+	DefaultMetricsEnabled bool = false
+
+	// DefaultMetricsRecheckInterval define how long to wait before running
+	// the metric queries again when a threshold is not satisfied
+	DefaultMetricsRecheckInterval time.Duration = 5 * time.Minute
+
+	// DefaultPolicyEnabled define if releases should only be published when
+	// the policy rules allow it
+	DefaultPolicyEnabled bool = false
+
+	// DefaultPolicyRecheckInterval define how long to wait before evaluating
+	// the policy rules again when a rule return pending
+	DefaultPolicyRecheckInterval time.Duration = 5 * time.Minute
+
+	// DefaultQuotaLimit define the maximum number of releases published within
+	// the quota period, 0 disable the quota
+	DefaultQuotaLimit int = 0
+
+	// DefaultQuotaPeriod define the period over which releases published in a
+	// repository are counted
+	DefaultQuotaPeriod time.Duration = 24 * time.Hour
+
+	// DefaultOrgQuotaPeriod define the period over which releases published
+	// across all the repositories of an owner are counted
+	DefaultOrgQuotaPeriod time.Duration = time.Hour
+
 	// DefaultReleaseNoteEnabled define if the statuses should be added to the
 	// release note
 	DefaultReleaseNoteEnabled bool = true
@@ -72,8 +199,87 @@ Last time GRGate processed this repository: {{ .LastExecutionTime }}`
 {{- end }}
 
 </details>
+{{- if .Blockers }}
+
+<details><summary>Release blockers</summary>
+{{ range .Blockers }}
+- [{{ .Title }}]({{ .URL }})
+{{- end }}
+
+</details>
+{{- end }}
 <!-- GRGate end -->`
 
+	// DefaultRollbackEnabled define if published releases should be reverted
+	// to draft when a required status fail after publication
+	DefaultRollbackEnabled bool = false
+
+	// DefaultRollbackWindow define for how long a published release is
+	// watched for failing statuses
+	DefaultRollbackWindow time.Duration = 24 * time.Hour
+
+	// DefaultRollbackMarkerStart is the string that define the start of the
+	// rollback notice added to the release note
+	DefaultRollbackMarkerStart string = "<!-- GRGate rollback start -->"
+
+	// DefaultRollbackMarkerEnd is the string that define the end of the
+	// rollback notice added to the release note
+	DefaultRollbackMarkerEnd string = "<!-- GRGate rollback end -->"
+
+	// DefaultRetriesMarkerPrefix is the prefix of the hidden comment used to
+	// keep track of status retry attempts in the release note
+	DefaultRetriesMarkerPrefix string = "<!-- GRGate retries: "
+
+	// DefaultTriggersMarkerPrefix is the prefix of the hidden comment used to
+	// keep track of the workflows triggered for a release in the release note
+	DefaultTriggersMarkerPrefix string = "<!-- GRGate triggered: "
+
+	// DefaultTimeoutStartMarkerPrefix is the prefix of the hidden comment used
+	// to keep track of when the timeout of a release started in the release
+	// note
+	DefaultTimeoutStartMarkerPrefix string = "<!-- GRGate timeout started: "
+
+	// DefaultPluginsDir is the default directory where plugin executables
+	// are installed, repositories can only run plugins from this directory
+	DefaultPluginsDir string = "/etc/grgate/plugins"
+
+	// DefaultSignaturesDir is the default directory where the keyrings and
+	// allowed signers files used to verify signatures are installed
+	DefaultSignaturesDir string = "/etc/grgate/signatures"
+
+	// DefaultPluginTimeout is the default maximum duration of a plugin run
+	DefaultPluginTimeout time.Duration = time.Minute
+
+	// DefaultPluginMaxTimeout is the maximum duration of a plugin run,
+	// timeouts defined by repositories are capped to this value
+	DefaultPluginMaxTimeout time.Duration = 10 * time.Minute
+
+	// DefaultPluginsRecheckInterval define how long to wait before running the
+	// plugins again when a plugin return pending
+	DefaultPluginsRecheckInterval time.Duration = 5 * time.Minute
+
+	// DefaultOrgConfigRepository is the default repository of an organization
+	// (Github) or group (Gitlab) which store the organization default
+	// repository config
+	DefaultOrgConfigRepository string = ".github"
+
+	// DefaultOrgConfigPath is the default path of the organization default
+	// repository config
+	DefaultOrgConfigPath string = "grgate.yaml"
+
+	// RepositoryTopicPrefix is the prefix of the repository patterns matched
+	// against the repository topics instead of its name
+	RepositoryTopicPrefix string = "topic:"
+
+	// DefaultRepoConfigCacheTTL define for how long the config files read from
+	// repositories are cached, missing files included. Push events changing a
+	// file invalidate it before, changes not listed by a push event, ie: a
+	// force-push, are only picked up once the file expires
+	DefaultRepoConfigCacheTTL time.Duration = 5 * time.Minute
+
+	// DefaultRepoConfigMode define from where the repository config is read
+	DefaultRepoConfigMode string = RepoConfigModeDefaultBranch
+
 	// DefaultRepoConfigPath is the default path of the .grgate config stored in
 	// the repository
 	DefaultRepoConfigPath string = ".grgate.yaml"
@@ -95,31 +301,98 @@ Last time GRGate processed this repository: {{ .LastExecutionTime }}`
 
 	// GitlabPlatform represent the Gitlab platform
 	GitlabPlatform PlatformType = "gitlab"
+
+	// RepoConfigModeDefaultBranch read the repository config from the default
+	// branch of the repository
+	RepoConfigModeDefaultBranch string = "defaultBranch"
+
+	// RepoConfigModeRef read the repository config from the branch, tag or
+	// commit defined by repoConfigRef
+	RepoConfigModeRef string = "ref"
+
+	// RepoConfigModeRelease read the repository config from the commit of each
+	// release so that each release line can define its own gating rules
+	RepoConfigModeRelease string = "release"
 )
 
-// MainConfig define the main configuration
+// MainConfig define the main configuration. RepoConfigPath is the ordered
+// list of candidate paths of the repository config, the first file found is
+// used and its format (YAML, JSON or TOML) is detected from its extension.
+// The secret signing the external decision requests is read from
+// ExternalSecretFile if defined
 type MainConfig struct {
-	Github         *Github       `mapstructure:"github"`
-	Gitlab         *Gitlab       `mapstructure:"gitlab"`
-	Globals        *RepoConfig   `mapstructure:"globals"`
-	LogFormat      string        `mapstructure:"logFormat"`
-	LogLevel       string        `mapstructure:"logLevel"`
-	Platform       *PlatformType `mapstructure:"platform"`
-	RepoConfigPath string        `mapstructure:"repoConfigPath"`
-	Server         *Server       `mapstructure:"server"`
-	Workers        int           `mapstructure:"workers"`
-}
-
-// Github define Github configuration
+	ExternalSecret     string        `mapstructure:"externalSecret"`
+	ExternalSecretFile string        `mapstructure:"externalSecretFile"`
+	Github             *Github       `mapstructure:"github"`
+	Gitlab             *Gitlab       `mapstructure:"gitlab"`
+	Globals            *RepoConfig   `mapstructure:"globals"`
+	LogFormat          string        `mapstructure:"logFormat"`
+	LogLevel           string        `mapstructure:"logLevel"`
+	OrgConfig          *OrgConfig    `mapstructure:"orgConfig"`
+	OrgQuota           *Quota        `mapstructure:"orgQuota"`
+	Platform           *PlatformType `mapstructure:"platform"`
+	PluginMaxTimeout   time.Duration `mapstructure:"pluginMaxTimeout"`
+	PluginsDir         string        `mapstructure:"pluginsDir"`
+	RepoConfigCacheTTL time.Duration `mapstructure:"repoConfigCacheTTL"`
+	RepoConfigMode     string        `mapstructure:"repoConfigMode"`
+	RepoConfigPath     []string      `mapstructure:"repoConfigPath"`
+	RepoConfigRef      string        `mapstructure:"repoConfigRef"`
+	Repositories       *Repositories `mapstructure:"repositories"`
+	Server             *Server       `mapstructure:"server"`
+	SignaturesDir      string        `mapstructure:"signaturesDir"`
+	Workers            int           `mapstructure:"workers"`
+}
+
+// OrgConfig define where the organization default repository config is
+// stored, settings defined in this file apply to all the repositories of the
+// organization (Github) or group (Gitlab) unless overridden by the repository
+// config. The organization config is disabled when Repository is empty
+type OrgConfig struct {
+	Repository string `mapstructure:"repository"`
+	Path       string `mapstructure:"path"`
+}
+
+// Repositories define which repositories are processed using glob patterns
+// matched against "owner/name", ie: "my-org/*". A pattern ending with "/**"
+// match all the repositories of a Gitlab group and its subgroups, patterns
+// prefixed by "topic:" match the repository topics. If Include is empty all
+// the repositories are included, excluded repositories are never processed
+type Repositories struct {
+	Include []string `mapstructure:"include"`
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// Github define Github configuration, PrivateKey is an inline PEM or base64
+// encoded PEM private key used instead of PrivateKeyPath
 type Github struct {
 	AppID          int64  `mapstructure:"appID"`
 	InstallationID int64  `mapstructure:"installationID"`
+	PrivateKey     string `mapstructure:"privateKey"`
 	PrivateKeyPath string `mapstructure:"privateKeyPath"`
 }
 
-// Gitlab define Gitlab configuration
+// Gitlab define Gitlab configuration, the token is read from TokenFile if
+// defined
 type Gitlab struct {
-	Token string `mapstructure:"token"`
+	Token     string `mapstructure:"token"`
+	TokenFile string `mapstructure:"tokenFile"`
+}
+
+// Assets define the release assets gate configuration
+type Assets struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	Required     []string `mapstructure:"required"`
+	ChecksumFile string   `mapstructure:"checksumFile"`
+}
+
+// Blockers define the issue blockers gate configuration, releases are held
+// while open issues with one of the labels, or attached to the milestone
+// named after the release tag, exist
+type Blockers struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	Labels       []string `mapstructure:"labels"`
+	Milestone    bool     `mapstructure:"milestone"`
+	PullRequests bool     `mapstructure:"pullRequests"`
 }
 
 // Dashboard define the issue dashboard configuration
@@ -130,25 +403,196 @@ type Dashboard struct {
 	Template string `mapstructure:"template"`
 }
 
+// External define the external decision gate configuration. Release data are
+// sent to the URL which decide if the release can be published. If the
+// externalSecret of the main config is defined, the request body is signed
+// using HMAC SHA256, the secret can't be defined by a repository config
+type External struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	URL             string        `mapstructure:"url"`
+	RecheckInterval time.Duration `mapstructure:"recheckInterval"`
+}
+
+// Metrics define the metrics gate configuration, each query is run against
+// the Prometheus compatible API URL
+type Metrics struct {
+	Enabled         bool           `mapstructure:"enabled"`
+	URL             string         `mapstructure:"url"`
+	Queries         []*MetricQuery `mapstructure:"queries"`
+	RecheckInterval time.Duration  `mapstructure:"recheckInterval"`
+}
+
+// MetricQuery define a PromQL query and the threshold its result is compared
+// to. The query is a template rendered with the release data, ie:
+// {{ .Tag }} or {{ .CommitSha }}
+type MetricQuery struct {
+	Name      string  `mapstructure:"name"`
+	Query     string  `mapstructure:"query"`
+	Operator  string  `mapstructure:"operator"`
+	Threshold float64 `mapstructure:"threshold"`
+}
+
+// Plugin define an executable run as a gate. The command is the name of an
+// executable installed in the plugins directory, it receives the release
+// context as JSON on stdin and write its verdict as JSON to stdout
+type Plugin struct {
+	Name    string        `mapstructure:"name"`
+	Command string        `mapstructure:"command"`
+	Args    []string      `mapstructure:"args"`
+	Env     []*PluginEnv  `mapstructure:"env"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// PluginEnv define an environment variable passed to a plugin, variables are
+// defined as a list to preserve the case of their name. Reserved variables,
+// ie: PATH, HOME or LD_PRELOAD, are rejected
+type PluginEnv struct {
+	Name  string `mapstructure:"name"`
+	Value string `mapstructure:"value"`
+}
+
+// Policy define the policy gate configuration, each rule is a CEL expression
+// evaluated against the release, its statuses, the release commit and the
+// current time
+type Policy struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Rules           []*PolicyRule `mapstructure:"rules"`
+	RecheckInterval time.Duration `mapstructure:"recheckInterval"`
+}
+
+// PolicyRule define a CEL expression returning either a boolean or one of the
+// allow, deny or pending decisions. The message is displayed when the rule
+// doesn't allow the release
+type PolicyRule struct {
+	Name       string `mapstructure:"name"`
+	Expression string `mapstructure:"expression"`
+	Message    string `mapstructure:"message"`
+}
+
+// Quota define the maximum number of releases published within a period,
+// releases over the quota are kept as draft until the quota allow them.
+// Publishes are counted in memory: the releases published before GRGate
+// started are counted once their repository is processed, therefore the
+// organization quota only counts the repositories processed since GRGate
+// started
+type Quota struct {
+	Limit  int           `mapstructure:"limit"`
+	Period time.Duration `mapstructure:"period"`
+}
+
 // ReleaseNote define the release note configuration
 type ReleaseNote struct {
 	Enabled  bool   `mapstructure:"enabled"`
 	Template string `mapstructure:"template"`
 }
 
+// Rollback define the rollback configuration, when enabled published releases
+// are reverted to draft if a required status fail within the window
+type Rollback struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Window  time.Duration `mapstructure:"window"`
+}
+
+// Signature define the signature verification gate configuration. Keyring
+// and AllowedSigners are paths of files used to verify signatures locally,
+// relative to the signaturesDir of the main config. AllowedKeyIDs only apply
+// to GPG signatures, SSH signatures require AllowedSigners when it is set.
+// Allowed key IDs are long key IDs (16 hexadecimal characters) or
+// fingerprints, a fingerprint only match signatures verified with a keyring
+// since platforms only report the long key ID of the signing key
+type Signature struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Target         string   `mapstructure:"target"`
+	AllowedKeyIDs  []string `mapstructure:"allowedKeyIDs"`
+	Keyring        string   `mapstructure:"keyring"`
+	AllowedSigners string   `mapstructure:"allowedSigners"`
+}
+
+// StatusRule define additional rules applied to a required status. Retries
+// define how many times a failed status is retried before giving up
+type StatusRule struct {
+	Name    string        `mapstructure:"name"`
+	Retries int           `mapstructure:"retries"`
+	Source  *StatusSource `mapstructure:"source"`
+}
+
+// StatusSource define the trusted source of a status, statuses coming from
+// any other source are ignored. App is only supported on Github, Creator and
+// PipelineSource are only supported on Gitlab, unsupported fields are
+// reported as config errors
+type StatusSource struct {
+	App            string `mapstructure:"app"`
+	Creator        string `mapstructure:"creator"`
+	PipelineSource string `mapstructure:"pipelineSource"`
+}
+
+// Timeout define how long a draft release can wait for its required statuses
+// before being marked as blocked. The timeout starts when GRGate first find
+// the draft release or revert it to draft. Blocked releases are deleted after
+// the retention period if defined, unless they have been rolled back. A
+// notification is sent to the notification URL when a release is blocked
+type Timeout struct {
+	Duration        time.Duration `mapstructure:"duration"`
+	Retention       time.Duration `mapstructure:"retention"`
+	NotificationURL string        `mapstructure:"notificationURL"`
+}
+
+// Trigger define a workflow to dispatch when a new draft release is found. Ref
+// and inputs values are templates rendered with the release data, ie:
+// {{ .Tag }} or {{ .CommitSha }}
+type Trigger struct {
+	Name   string          `mapstructure:"name"`
+	Ref    string          `mapstructure:"ref"`
+	Inputs []*TriggerInput `mapstructure:"inputs"`
+}
+
+// TriggerInput define an input passed to a triggered workflow, inputs are
+// defined as a list to preserve the case of their name
+type TriggerInput struct {
+	Name  string `mapstructure:"name"`
+	Value string `mapstructure:"value"`
+}
+
 // RepoConfig define repository configuration
 type RepoConfig struct {
-	Enabled     bool         `mapstructure:"enabled"`
-	Dashboard   *Dashboard   `mapstructure:"dashboard"`
-	ReleaseNote *ReleaseNote `mapstructure:"releaseNote"`
-	Statuses    []string     `mapstructure:"statuses"`
-	TagRegexp   string       `mapstructure:"tagRegexp"`
+	Enabled                  bool          `mapstructure:"enabled"`
+	Assets                   *Assets       `mapstructure:"assets"`
+	Blockers                 *Blockers     `mapstructure:"blockers"`
+	Dashboard                *Dashboard    `mapstructure:"dashboard"`
+	DependsOn                []string      `mapstructure:"dependsOn"`
+	DependsOnRecheckInterval time.Duration `mapstructure:"dependsOnRecheckInterval"`
+	External                 *External     `mapstructure:"external"`
+	Metrics                  *Metrics      `mapstructure:"metrics"`
+	Plugins                  []*Plugin     `mapstructure:"plugins"`
+	PluginsRecheckInterval   time.Duration `mapstructure:"pluginsRecheckInterval"`
+	Policy                   *Policy       `mapstructure:"policy"`
+	Quota                    *Quota        `mapstructure:"quota"`
+	ReleaseNote              *ReleaseNote  `mapstructure:"releaseNote"`
+	Rollback                 *Rollback     `mapstructure:"rollback"`
+	Signature                *Signature    `mapstructure:"signature"`
+	Statuses                 []string      `mapstructure:"statuses"`
+	StatusesFrom             string        `mapstructure:"statusesFrom"`
+	StatusRules              []*StatusRule `mapstructure:"statusRules"`
+	TagRegexp                string        `mapstructure:"tagRegexp"`
+	Timeout                  *Timeout      `mapstructure:"timeout"`
+	Triggers                 []*Trigger    `mapstructure:"triggers"`
+
+	// Errors found while validating the repository config file, the default
+	// settings are used when the file is invalid
+	Errors []string `mapstructure:"-"`
+
+	// Sources define where each setting came from, indexed by lowercased
+	// setting key, ie: "dashboard.title". Settings which are not listed use
+	// the default value
+	Sources map[string]string `mapstructure:"-"`
 }
 
-// Server define server configuration
+// Server define server configuration, the webhook secret is read from
+// WebhookSecretFile if defined
 type Server struct {
-	ListenAddress  string `mapstructure:"listenAddress"`
-	MetricsAddress string `mapstructure:"metricsAddress"`
-	ProbeAddress   string `mapstructure:"probeAddress"`
-	WebhookSecret  string `mapstructure:"webhookSecret"`
+	ListenAddress     string `mapstructure:"listenAddress"`
+	MetricsAddress    string `mapstructure:"metricsAddress"`
+	ProbeAddress      string `mapstructure:"probeAddress"`
+	WebhookSecret     string `mapstructure:"webhookSecret"`
+	WebhookSecretFile string `mapstructure:"webhookSecretFile"`
 }
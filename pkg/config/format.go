@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatJSON is the format of config files with the .json extension
+	FormatJSON string = "json"
+
+	// FormatTOML is the format of config files with the .toml extension
+	FormatTOML string = "toml"
+
+	// FormatYAML is the format of the other config files
+	FormatYAML string = "yaml"
+)
+
+// ConfigFormat returns the format of a config file detected from its
+// extension, files without a known extension are parsed as YAML
+func ConfigFormat(filePath string) string {
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatYAML
+}
+
+// parseNode parse the content of a config file as a yaml document so that
+// all the formats are validated the same way. JSON is a subset of YAML, TOML
+// documents are converted and their values don't have line numbers
+func parseNode(content []byte, format string) (*yaml.Node, *ValidationError) {
+	root := &yaml.Node{}
+
+	if format != FormatTOML {
+		if err := yaml.Unmarshal(content, root); err != nil {
+			return nil, yamlError(err)
+		}
+		return root, nil
+	}
+
+	var document map[string]interface{}
+	if err := toml.Unmarshal(content, &document); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, _ := decodeErr.Position()
+			return nil, &ValidationError{Line: line, Message: decodeErr.Error()}
+		}
+		return nil, &ValidationError{Message: err.Error()}
+	}
+
+	if len(document) == 0 {
+		return root, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(document); err != nil {
+		return nil, &ValidationError{Message: err.Error()}
+	}
+
+	root.Kind = yaml.DocumentNode
+	root.Content = []*yaml.Node{node}
+
+	return root, nil
+}
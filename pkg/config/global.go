@@ -1,12 +1,21 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
-// NewGlobalConfig define the viper configuration and set the global
-// config.Main variable base on a config file
-func NewGlobalConfig(path string) (v *viper.Viper, err error) {
+// LoadGlobalConfig read and validate the main config file, flags override the
+// settings of the file. If path is empty, /etc/grgate/config.yaml is read if
+// it exists
+func LoadGlobalConfig(path string, flags *pflag.FlagSet) (v *viper.Viper,
+	config *MainConfig, err error) {
 	v = viper.New()
 
 	if path != "" {
@@ -17,20 +26,55 @@ func NewGlobalConfig(path string) (v *viper.Viper, err error) {
 		v.SetConfigType("yaml")
 	}
 
-	v.SetEnvPrefix("grgate")
+	// every setting can be overridden by an environment variable named after
+	// its key, ie: GRGATE_GITLAB_TOKEN for gitlab.token
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	bindEnvs(v, reflect.TypeOf(MainConfig{}), nil)
 
 	// Set defaults
 	v.SetDefault("globals.enabled", DefaultEnabled)
+	v.SetDefault("globals.assets.enabled", DefaultAssetsEnabled)
+	v.SetDefault("globals.blockers.enabled", DefaultBlockersEnabled)
+	v.SetDefault("globals.blockers.labels", []string{DefaultBlockersLabel})
+	v.SetDefault("globals.blockers.milestone", DefaultBlockersMilestone)
+	v.SetDefault("globals.blockers.pullRequests", DefaultBlockersPullRequests)
 	v.SetDefault("globals.dashboard.enabled", DefaultDashboardEnabled)
 	v.SetDefault("globals.dashboard.author", DefaultDashboardAuthor)
 	v.SetDefault("globals.dashboard.title", DefaultDashboardTitle)
 	v.SetDefault("globals.dashboard.template", DefaultDashboardTemplate)
+	v.SetDefault("globals.external.enabled", DefaultExternalEnabled)
+	v.SetDefault("globals.dependsOnRecheckInterval", DefaultDependsOnRecheckInterval)
+	v.SetDefault("globals.external.recheckInterval", DefaultExternalRecheckInterval)
+	v.SetDefault("globals.metrics.enabled", DefaultMetricsEnabled)
+	v.SetDefault("globals.metrics.recheckInterval", DefaultMetricsRecheckInterval)
+	v.SetDefault("globals.pluginsRecheckInterval", DefaultPluginsRecheckInterval)
+	v.SetDefault("globals.policy.enabled", DefaultPolicyEnabled)
+	v.SetDefault("globals.policy.recheckInterval", DefaultPolicyRecheckInterval)
+	v.SetDefault("globals.quota.limit", DefaultQuotaLimit)
+	v.SetDefault("globals.quota.period", DefaultQuotaPeriod)
 	v.SetDefault("globals.releaseNote.enabled", DefaultReleaseNoteEnabled)
 	v.SetDefault("globals.releaseNote.template", DefaultReleaseNoteTemplate)
+	v.SetDefault("globals.rollback.enabled", DefaultRollbackEnabled)
+	v.SetDefault("globals.rollback.window", DefaultRollbackWindow)
+	v.SetDefault("globals.signature.enabled", DefaultSignatureEnabled)
+	v.SetDefault("globals.signature.target", DefaultSignatureTarget)
 	v.SetDefault("globals.tagRegexp", DefaultTagRegexp)
+	v.SetDefault("globals.timeout.duration", DefaultTimeoutDuration)
+	v.SetDefault("globals.timeout.retention", DefaultTimeoutRetention)
+	v.SetDefault("orgConfig.repository", DefaultOrgConfigRepository)
+	v.SetDefault("orgConfig.path", DefaultOrgConfigPath)
+	v.SetDefault("orgQuota.limit", DefaultQuotaLimit)
+	v.SetDefault("orgQuota.period", DefaultOrgQuotaPeriod)
 	v.SetDefault("platform", DefaultPlatform)
-	v.SetDefault("repoConfigPath", DefaultRepoConfigPath)
+	v.SetDefault("pluginMaxTimeout", DefaultPluginMaxTimeout)
+	v.SetDefault("pluginsDir", DefaultPluginsDir)
+	v.SetDefault("repoConfigCacheTTL", DefaultRepoConfigCacheTTL)
+	v.SetDefault("repoConfigMode", DefaultRepoConfigMode)
+	v.SetDefault("repoConfigPath", []string{DefaultRepoConfigPath})
 	v.SetDefault("server.listenAddress", DefaultServerListenAddress)
+	v.SetDefault("signaturesDir", DefaultSignaturesDir)
 	v.SetDefault("server.metricsAddress", DefaultServerMetricsAddress)
 	v.SetDefault("server.probeAddress", DefaultServerProbeAddress)
 	v.SetDefault("workers", DefaultWorkers)
@@ -43,5 +87,117 @@ func NewGlobalConfig(path string) (v *viper.Viper, err error) {
 		}
 	}
 
-	return v, v.Unmarshal(&Main)
+	if file := v.ConfigFileUsed(); file != "" {
+		if content, err := os.ReadFile(file); err == nil {
+			if errs := ValidateMainConfig(content, ConfigFormat(file)); len(errs) > 0 {
+				messages := make([]string, 0, len(errs))
+				for _, e := range errs {
+					messages = append(messages, e.Error())
+				}
+				return v, nil, fmt.Errorf("invalid config file %s: %s", file,
+					strings.Join(messages, ", "))
+			}
+		}
+	}
+
+	if flags != nil {
+		if err = v.BindPFlags(flags); err != nil {
+			return
+		}
+	}
+
+	if err = v.Unmarshal(&config); err != nil {
+		return
+	}
+
+	if err = resolveSecrets(config); err != nil {
+		return
+	}
+
+	config.Globals.Sources = GlobalSources(v, flags)
+
+	return v, config, nil
+}
+
+// GlobalSources returns where each globals setting came from, indexed by
+// lowercased setting key without the globals prefix. Flags take precedence
+// over environment variables which take precedence over the config file,
+// settings which are not listed use the default value
+func GlobalSources(v *viper.Viper, flags *pflag.FlagSet) (sources map[string]string) {
+	for _, key := range v.AllKeys() {
+		name := strings.TrimPrefix(key, "globals.")
+		if name == key {
+			continue
+		}
+
+		source := ""
+		if flags != nil {
+			flags.VisitAll(func(flag *pflag.Flag) {
+				if flag.Changed && strings.EqualFold(flag.Name, key) {
+					source = SourceFlag
+				}
+			})
+		}
+		if _, ok := os.LookupEnv(EnvVar(key)); source == "" && ok {
+			source = SourceEnv
+		}
+		if source == "" && v.InConfig(key) {
+			source = v.ConfigFileUsed()
+		}
+		if source == "" {
+			continue
+		}
+
+		if sources == nil {
+			sources = make(map[string]string)
+		}
+		sources[name] = source
+	}
+	return
+}
+
+// envPrefix is the prefix of the environment variables overriding settings
+const envPrefix = "grgate"
+
+// EnvVar returns the name of the environment variable overriding a setting,
+// ie: GRGATE_GLOBALS_DASHBOARD_TITLE for globals.dashboard.title
+func EnvVar(key string) string {
+	return strings.ToUpper(envPrefix + "_" + strings.ReplaceAll(key, ".", "_"))
+}
+
+// bindEnvs bind an environment variable to each setting of the config type so
+// that settings without default value can be set from the environment. Lists
+// of objects can't be defined by an environment variable and are skipped,
+// lists of strings are comma separated
+func bindEnvs(v *viper.Viper, t reflect.Type, path []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := append(path[:len(path):len(path)], tag)
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		switch {
+		case fieldType.Kind() == reflect.Struct &&
+			fieldType != reflect.TypeOf(time.Duration(0)):
+			bindEnvs(v, fieldType, key)
+		case fieldType.Kind() == reflect.Slice &&
+			fieldType.Elem().Kind() != reflect.String:
+			continue
+		default:
+			// BindEnv only fails when no key is provided
+			_ = v.BindEnv(strings.Join(key, "."))
+		}
+	}
 }
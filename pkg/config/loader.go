@@ -0,0 +1,29 @@
+package config
+
+import (
+	"github.com/rs/zerolog"
+)
+
+// Loader read repository configs with the settings of a main config. The
+// config files read from repositories are cached by the loader, a new loader
+// is created when the main config changes so that its cache starts empty
+type Loader struct {
+	config *MainConfig
+	logger zerolog.Logger
+	cache  *fileCache
+}
+
+// NewLoader returns a Loader which read repository configs with the globals,
+// organization config and repository config settings of mainConfig
+func NewLoader(mainConfig *MainConfig, logger zerolog.Logger) *Loader {
+	return &Loader{
+		config: mainConfig,
+		logger: logger,
+		cache:  newFileCache(),
+	}
+}
+
+// Config returns the main config of the loader
+func (l *Loader) Config() *MainConfig {
+	return l.config
+}
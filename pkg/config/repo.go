@@ -2,57 +2,293 @@ package config
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"strings"
 
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 
 	"github.com/fikaworks/grgate/pkg/platforms"
 )
 
-// NewRepoConfig returns configuration defined in a repository
-func NewRepoConfig(platform platforms.Platform, owner, repository string) (config *RepoConfig, err error) {
-	cfg, err := platform.ReadFile(owner, repository, Main.RepoConfigPath)
+const (
+	// SourceDefault is the source of the settings which are not defined in any
+	// config file or flag
+	SourceDefault string = "default"
+
+	// SourceEnv is the source of the settings defined by an environment
+	// variable
+	SourceEnv string = "env"
+
+	// SourceFlag is the source of the settings defined by a command line flag
+	SourceFlag string = "flag"
+)
+
+// configLayer define the content of a config file merged into the repository
+// config, source is the name of the file used in errors and sources
+type configLayer struct {
+	source  string
+	format  string
+	content []byte
+	errors  []string
+}
+
+// RepoConfig returns configuration defined in a repository, settings are
+// merged from the globals, the organization config then the repository config.
+// The repository config is read from the ref defined by repoConfigMode
+func (l *Loader) RepoConfig(platform platforms.Platform, owner, repository string) (*RepoConfig, error) {
+	var ref string
+	if l.config.RepoConfigMode == RepoConfigModeRef {
+		ref = l.config.RepoConfigRef
+	}
+	return l.RepoConfigAtRef(platform, owner, repository, ref)
+}
+
+// RepoConfigAtRef returns configuration defined in a repository at the given
+// branch, tag or commit, an empty ref is the default branch. The organization
+// config is always read from its default branch
+func (l *Loader) RepoConfigAtRef(platform platforms.Platform, owner, repository,
+	ref string) (config *RepoConfig, err error) {
+	mainConfig := l.config
+
+	var layers []*configLayer
+
+	if mainConfig.OrgConfig != nil && mainConfig.OrgConfig.Repository != "" {
+		var layer *configLayer
+		layer, err = l.readConfigLayer(platform, owner, mainConfig.OrgConfig.Repository,
+			[]string{mainConfig.OrgConfig.Path}, "", fmt.Sprintf("%s/%s/", owner,
+				mainConfig.OrgConfig.Repository))
+		if err != nil {
+			return
+		}
+		if layer != nil {
+			layers = append(layers, layer)
+		}
+	}
+
+	layer, err := l.readConfigLayer(platform, owner, repository,
+		mainConfig.RepoConfigPath, ref, "")
 	if err != nil {
-		log.Info().
+		return
+	}
+	if layer != nil {
+		layers = append(layers, layer)
+	}
+
+	var validationErrors []string
+	for _, layer := range layers {
+		validationErrors = append(validationErrors, layer.errors...)
+	}
+
+	if len(validationErrors) > 0 {
+		l.logger.Warn().
 			Str("owner", owner).
 			Str("repository", repository).
-			Msgf("File \"%s\" not found in repository, using default settings",
-				Main.RepoConfigPath)
-		cfg = bytes.NewBuffer([]byte{})
-	} else {
-		log.Info().
+			Msgf("Invalid config: %s", strings.Join(validationErrors, ", "))
+	}
+
+	config, err = decodeRepoConfig(mainConfig, layers)
+	if err != nil && len(validationErrors) > 0 {
+		// the errors are reported in the dashboard which require the settings
+		// of the valid files when a file can't be decoded
+		var valid []*configLayer
+		for _, layer := range layers {
+			if len(layer.errors) == 0 {
+				valid = append(valid, layer)
+			}
+		}
+		config, err = decodeRepoConfig(mainConfig, valid)
+	}
+	if err != nil {
+		l.logger.Error().
+			Err(err).
+			Str("owner", owner).
+			Str("repository", repository).
+			Msg("couldn't unmarshal repo config")
+		return
+	}
+
+	config.Errors = validationErrors
+
+	if mainConfig.Platform != nil {
+		var messages []string
+		for _, setting := range unsupportedSettings(*mainConfig.Platform, config) {
+			messages = append(messages, setting.message)
+		}
+		if len(messages) > 0 {
+			l.logger.Warn().
+				Str("owner", owner).
+				Str("repository", repository).
+				Msgf("Unsupported settings: %s", strings.Join(messages, ", "))
+			config.Errors = append(config.Errors, messages...)
+		}
+	}
+
+	return config, nil
+}
+
+// Source returns where the setting came from, key is the setting path, ie:
+// "dashboard.title"
+func (c *RepoConfig) Source(key string) string {
+	if source, ok := c.Sources[strings.ToLower(key)]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// ReadRepoConfigFile returns the path and content of the first repository
+// config file found at ref in the order of the repoConfigPath candidates, an
+// empty ref is the default branch
+func (l *Loader) ReadRepoConfigFile(platform platforms.Platform, owner, repository,
+	ref string) (path string, content []byte, err error) {
+	return l.readFirstFile(platform, owner, repository, l.config.RepoConfigPath, ref)
+}
+
+// readFirstFile returns the path and content of the first file found at ref
+// in the order of the candidate paths. The next candidate is only read if the
+// file doesn't exist, other errors are returned. An error wrapping
+// platforms.ErrFileNotFound is returned if none of the files exist
+func (l *Loader) readFirstFile(platform platforms.Platform, owner, repository string,
+	paths []string, ref string) (path string, content []byte, err error) {
+	for _, path = range paths {
+		content, err = l.readCachedFile(platform, owner, repository, path, ref)
+		if err == nil {
+			return path, content, nil
+		}
+		if !errors.Is(err, platforms.ErrFileNotFound) {
+			return "", nil, fmt.Errorf("couldn't read file \"%s\" from %s/%s: %w",
+				path, owner, repository, err)
+		}
+	}
+	return "", nil, fmt.Errorf("%w: none of the files \"%s\" found in %s/%s",
+		platforms.ErrFileNotFound, strings.Join(paths, "\", \""), owner, repository)
+}
+
+// readConfigLayer read and validate the first config file found in a
+// repository at ref, it returns nil if none of the files exist. Other errors
+// are returned so that the settings of the layer are not replaced by the
+// defaults. The source of the layer is the path of the file prefixed by
+// sourcePrefix
+func (l *Loader) readConfigLayer(platform platforms.Platform, owner, repository string,
+	paths []string, ref, sourcePrefix string) (layer *configLayer, err error) {
+	path, content, err := l.readFirstFile(platform, owner, repository, paths, ref)
+	if err != nil && !errors.Is(err, platforms.ErrFileNotFound) {
+		l.logger.Error().
+			Err(err).
 			Str("owner", owner).
 			Str("repository", repository).
-			Msgf("Found file \"%s\" in repository, overriding settings",
-				Main.RepoConfigPath)
+			Msg("Couldn't read config file")
+		return nil, err
+	}
+	if err != nil {
+		l.logger.Info().
+			Str("owner", owner).
+			Str("repository", repository).
+			Msgf("File \"%s\" not found in repository, using default settings",
+				strings.Join(paths, "\", \""))
+		return nil, nil
+	}
+
+	l.logger.Info().
+		Str("owner", owner).
+		Str("repository", repository).
+		Msgf("Found file \"%s\" in repository, overriding settings", path)
+
+	source := sourcePrefix + path
+	layer = &configLayer{source: source, format: ConfigFormat(path), content: content}
+	for _, e := range ValidateRepoConfig(content, layer.format) {
+		layer.errors = append(layer.errors, fmt.Sprintf("%s: %s", source, e))
 	}
 
+	return layer, nil
+}
+
+// decodeRepoConfig deep merge the config layers in order, settings which are
+// not defined in any layer default to the globals settings
+func decodeRepoConfig(mainConfig *MainConfig, layers []*configLayer) (config *RepoConfig, err error) {
 	v := viper.New()
 	v.SetConfigType("yaml")
 
 	// Set defaults
-	v.SetDefault("enabled", Main.Globals.Enabled)
-	v.SetDefault("dashboard.enabled", Main.Globals.Dashboard.Enabled)
-	v.SetDefault("dashboard.author", Main.Globals.Dashboard.Author)
-	v.SetDefault("dashboard.title", Main.Globals.Dashboard.Title)
-	v.SetDefault("dashboard.template", Main.Globals.Dashboard.Template)
-	v.SetDefault("releaseNote.enabled", Main.Globals.ReleaseNote.Enabled)
-	v.SetDefault("releaseNote.template", Main.Globals.ReleaseNote.Template)
-	v.SetDefault("statuses", Main.Globals.Statuses)
-	v.SetDefault("tagRegexp", Main.Globals.TagRegexp)
-
-	if err = v.ReadConfig(cfg); err != nil {
-		return
+	v.SetDefault("enabled", mainConfig.Globals.Enabled)
+	v.SetDefault("assets.enabled", mainConfig.Globals.Assets.Enabled)
+	v.SetDefault("assets.required", mainConfig.Globals.Assets.Required)
+	v.SetDefault("assets.checksumFile", mainConfig.Globals.Assets.ChecksumFile)
+	v.SetDefault("blockers.enabled", mainConfig.Globals.Blockers.Enabled)
+	v.SetDefault("blockers.labels", mainConfig.Globals.Blockers.Labels)
+	v.SetDefault("blockers.milestone", mainConfig.Globals.Blockers.Milestone)
+	v.SetDefault("blockers.pullRequests", mainConfig.Globals.Blockers.PullRequests)
+	v.SetDefault("dashboard.enabled", mainConfig.Globals.Dashboard.Enabled)
+	v.SetDefault("dashboard.author", mainConfig.Globals.Dashboard.Author)
+	v.SetDefault("dashboard.title", mainConfig.Globals.Dashboard.Title)
+	v.SetDefault("dashboard.template", mainConfig.Globals.Dashboard.Template)
+	v.SetDefault("dependsOn", mainConfig.Globals.DependsOn)
+	v.SetDefault("dependsOnRecheckInterval", mainConfig.Globals.DependsOnRecheckInterval)
+	v.SetDefault("external.enabled", mainConfig.Globals.External.Enabled)
+	v.SetDefault("external.url", mainConfig.Globals.External.URL)
+	v.SetDefault("external.recheckInterval", mainConfig.Globals.External.RecheckInterval)
+	v.SetDefault("metrics.enabled", mainConfig.Globals.Metrics.Enabled)
+	v.SetDefault("metrics.url", mainConfig.Globals.Metrics.URL)
+	v.SetDefault("metrics.queries", mainConfig.Globals.Metrics.Queries)
+	v.SetDefault("metrics.recheckInterval", mainConfig.Globals.Metrics.RecheckInterval)
+	v.SetDefault("plugins", mainConfig.Globals.Plugins)
+	v.SetDefault("pluginsRecheckInterval", mainConfig.Globals.PluginsRecheckInterval)
+	v.SetDefault("policy.enabled", mainConfig.Globals.Policy.Enabled)
+	v.SetDefault("policy.rules", mainConfig.Globals.Policy.Rules)
+	v.SetDefault("policy.recheckInterval", mainConfig.Globals.Policy.RecheckInterval)
+	v.SetDefault("quota.limit", mainConfig.Globals.Quota.Limit)
+	v.SetDefault("quota.period", mainConfig.Globals.Quota.Period)
+	v.SetDefault("releaseNote.enabled", mainConfig.Globals.ReleaseNote.Enabled)
+	v.SetDefault("releaseNote.template", mainConfig.Globals.ReleaseNote.Template)
+	v.SetDefault("rollback.enabled", mainConfig.Globals.Rollback.Enabled)
+	v.SetDefault("rollback.window", mainConfig.Globals.Rollback.Window)
+	v.SetDefault("signature.enabled", mainConfig.Globals.Signature.Enabled)
+	v.SetDefault("signature.target", mainConfig.Globals.Signature.Target)
+	v.SetDefault("signature.allowedKeyIDs", mainConfig.Globals.Signature.AllowedKeyIDs)
+	v.SetDefault("signature.keyring", mainConfig.Globals.Signature.Keyring)
+	v.SetDefault("signature.allowedSigners", mainConfig.Globals.Signature.AllowedSigners)
+	v.SetDefault("statuses", mainConfig.Globals.Statuses)
+	v.SetDefault("statusesFrom", mainConfig.Globals.StatusesFrom)
+	v.SetDefault("statusRules", mainConfig.Globals.StatusRules)
+	v.SetDefault("tagRegexp", mainConfig.Globals.TagRegexp)
+	v.SetDefault("timeout.duration", mainConfig.Globals.Timeout.Duration)
+	v.SetDefault("timeout.retention", mainConfig.Globals.Timeout.Retention)
+	v.SetDefault("timeout.notificationURL", mainConfig.Globals.Timeout.NotificationURL)
+	v.SetDefault("triggers", mainConfig.Globals.Triggers)
+
+	var sources map[string]string
+	for key, source := range mainConfig.Globals.Sources {
+		if sources == nil {
+			sources = make(map[string]string)
+		}
+		sources[key] = source
+	}
+
+	for _, layer := range layers {
+		l := viper.New()
+		l.SetConfigType(layer.format)
+		if err = l.ReadConfig(bytes.NewReader(layer.content)); err != nil {
+			return
+		}
+		for _, key := range l.AllKeys() {
+			if sources == nil {
+				sources = make(map[string]string)
+			}
+			sources[key] = layer.source
+		}
+
+		// layers are merged with the parser of their own format
+		v.SetConfigType(layer.format)
+		if err = v.MergeConfig(bytes.NewReader(layer.content)); err != nil {
+			return
+		}
 	}
 
 	if err = v.Unmarshal(&config); err != nil {
-		log.Error().
-			Err(err).
-			Str("owner", owner).
-			Str("repository", repository).
-			Msg("couldn't unmarshal repo config")
 		return
 	}
 
+	config.Sources = sources
+
 	return config, nil
 }
@@ -7,7 +7,9 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/fikaworks/grgate/pkg/platforms"
 	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
 
 	"github.com/golang/mock/gomock"
@@ -26,31 +28,77 @@ func TestNewRepo(t *testing.T) {
 
 			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
 
-			mockPlatforms.EXPECT().ReadFile(gomock.Any(), gomock.Any(), gomock.Any()).
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				Return(nil, platforms.ErrFileNotFound)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "").
 				DoAndReturn(
-					func(_ string, _ string, _ string) (io.Reader, error) {
-						return nil, errors.New("file not found")
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return nil, platforms.ErrFileNotFound
 					})
 
 			expectedRepoConfig := RepoConfig{
 				Enabled: DefaultEnabled,
+				Assets: &Assets{
+					Enabled:  DefaultAssetsEnabled,
+					Required: []string{},
+				},
+				Blockers: &Blockers{
+					Enabled:      DefaultBlockersEnabled,
+					Labels:       []string{DefaultBlockersLabel},
+					Milestone:    DefaultBlockersMilestone,
+					PullRequests: DefaultBlockersPullRequests,
+				},
 				Dashboard: &Dashboard{
 					Enabled:  DefaultDashboardEnabled,
 					Author:   DefaultDashboardAuthor,
 					Title:    DefaultDashboardTitle,
 					Template: DefaultDashboardTemplate,
 				},
+				DependsOn:                []string{},
+				DependsOnRecheckInterval: DefaultDependsOnRecheckInterval,
+				External: &External{
+					Enabled:         DefaultExternalEnabled,
+					RecheckInterval: DefaultExternalRecheckInterval,
+				},
+				Metrics: &Metrics{
+					Enabled:         DefaultMetricsEnabled,
+					RecheckInterval: DefaultMetricsRecheckInterval,
+				},
+				PluginsRecheckInterval: DefaultPluginsRecheckInterval,
+				Policy: &Policy{
+					Enabled:         DefaultPolicyEnabled,
+					RecheckInterval: DefaultPolicyRecheckInterval,
+				},
+				Quota: &Quota{
+					Limit:  DefaultQuotaLimit,
+					Period: DefaultQuotaPeriod,
+				},
 				ReleaseNote: &ReleaseNote{
 					Enabled:  DefaultReleaseNoteEnabled,
 					Template: DefaultReleaseNoteTemplate,
 				},
+				Rollback: &Rollback{
+					Enabled: DefaultRollbackEnabled,
+					Window:  DefaultRollbackWindow,
+				},
+				Signature: &Signature{
+					Enabled:       DefaultSignatureEnabled,
+					Target:        DefaultSignatureTarget,
+					AllowedKeyIDs: []string{},
+				},
 				Statuses:  []string{},
 				TagRegexp: ".*",
+				Timeout: &Timeout{
+					Duration:  DefaultTimeoutDuration,
+					Retention: DefaultTimeoutRetention,
+				},
 			}
 
-			_, _ = NewGlobalConfig("")
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
 
-			repoConfig, err := NewRepoConfig(mockPlatforms, "owner", "repository")
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
 			if err != nil {
 				t.Errorf("Error not expected: %#v", err)
 			}
@@ -68,49 +116,602 @@ func TestNewRepo(t *testing.T) {
 
 			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
 
-			mockPlatforms.EXPECT().ReadFile(gomock.Any(), gomock.Any(), gomock.Any()).
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				Return(nil, platforms.ErrFileNotFound)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "").
 				DoAndReturn(
-					func(_ string, _ string, _ string) (io.Reader, error) {
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
 						return strings.NewReader(`enabled: true
+assets:
+  enabled: true
+  required:
+    - "*.tar.gz"
+  checksumFile: checksums.txt
+blockers:
+  enabled: true
+  labels:
+    - blocker
+  milestone: false
+  pullRequests: true
 dashboard:
   enabled: false
   author: some author
   title: some title
   template: |-
     some template
+dependsOn:
+  - backend >= v2.3.0 published
+external:
+  enabled: true
+  url: https://change.example.com/decision
+  recheckInterval: 10m
+metrics:
+  enabled: true
+  url: http://prometheus:9090
+  queries:
+    - name: error rate
+      query: error_rate{version="{{ .Tag }}"}
+      operator: "<"
+      threshold: 0.05
+  recheckInterval: 2m
+plugins:
+  - name: license-scan
+    command: license-scan
+    args:
+      - --strict
+    env:
+      - name: SCAN_LEVEL
+        value: high
+    timeout: 5m
+pluginsRecheckInterval: 15m
+policy:
+  enabled: true
+  rules:
+    - name: major-soak
+      expression: release.bump != "major" || now - release.createdAt > duration("24h")
+      message: major releases require a 24h soak
+  recheckInterval: 30m
+quota:
+  limit: 3
+  period: 12h
 releaseNote:
   enabled: false
   template: |-
     some template
+rollback:
+  enabled: true
+  window: 2h
+signature:
+  enabled: true
+  target: commit
+  allowedKeyIDs:
+    - ABCDEF0123456789
 statuses:
-  - happy-flow`), nil
+  - happy-flow
+statusesFrom: branchProtection
+statusRules:
+  - name: happy-flow
+    retries: 2
+    source:
+      app: 1234
+timeout:
+  duration: 72h
+  retention: 168h
+  notificationURL: https://hooks.example.com/grgate
+triggers:
+  - name: e2e.yaml
+    ref: "{{ .CommitSha }}"
+    inputs:
+      - name: E2E_FLOW
+        value: happy-flow
+      - name: releaseTag
+        value: "{{ .Tag }}"`), nil
 					})
 
 			expectedRepoConfig := RepoConfig{
 				Enabled: true,
+				Assets: &Assets{
+					Enabled:      true,
+					Required:     []string{"*.tar.gz"},
+					ChecksumFile: "checksums.txt",
+				},
+				Blockers: &Blockers{
+					Enabled:      true,
+					Labels:       []string{"blocker"},
+					Milestone:    false,
+					PullRequests: true,
+				},
 				Dashboard: &Dashboard{
 					Enabled:  false,
 					Author:   "some author",
 					Title:    "some title",
 					Template: "some template",
 				},
+				DependsOn:                []string{"backend >= v2.3.0 published"},
+				DependsOnRecheckInterval: DefaultDependsOnRecheckInterval,
+				External: &External{
+					Enabled:         true,
+					URL:             "https://change.example.com/decision",
+					RecheckInterval: 10 * time.Minute,
+				},
+				Metrics: &Metrics{
+					Enabled: true,
+					URL:     "http://prometheus:9090",
+					Queries: []*MetricQuery{
+						{
+							Name:      "error rate",
+							Query:     `error_rate{version="{{ .Tag }}"}`,
+							Operator:  "<",
+							Threshold: 0.05,
+						},
+					},
+					RecheckInterval: 2 * time.Minute,
+				},
+				Plugins: []*Plugin{
+					{
+						Name:    "license-scan",
+						Command: "license-scan",
+						Args:    []string{"--strict"},
+						Env: []*PluginEnv{
+							{Name: "SCAN_LEVEL", Value: "high"},
+						},
+						Timeout: 5 * time.Minute,
+					},
+				},
+				PluginsRecheckInterval: 15 * time.Minute,
+				Policy: &Policy{
+					Enabled: true,
+					Rules: []*PolicyRule{
+						{
+							Name:       "major-soak",
+							Expression: `release.bump != "major" || now - release.createdAt > duration("24h")`,
+							Message:    "major releases require a 24h soak",
+						},
+					},
+					RecheckInterval: 30 * time.Minute,
+				},
+				Quota: &Quota{
+					Limit:  3,
+					Period: 12 * time.Hour,
+				},
 				ReleaseNote: &ReleaseNote{
 					Enabled:  false,
 					Template: "some template",
 				},
-				Statuses:  []string{"happy-flow"},
+				Rollback: &Rollback{
+					Enabled: true,
+					Window:  2 * time.Hour,
+				},
+				Signature: &Signature{
+					Enabled:       true,
+					Target:        "commit",
+					AllowedKeyIDs: []string{"ABCDEF0123456789"},
+				},
+				Statuses:     []string{"happy-flow"},
+				StatusesFrom: StatusesFromBranchProtection,
+				StatusRules: []*StatusRule{
+					{
+						Name:    "happy-flow",
+						Retries: 2,
+						Source: &StatusSource{
+							App: "1234",
+						},
+					},
+				},
 				TagRegexp: ".*",
+				Timeout: &Timeout{
+					Duration:        72 * time.Hour,
+					Retention:       168 * time.Hour,
+					NotificationURL: "https://hooks.example.com/grgate",
+				},
+				Triggers: []*Trigger{
+					{
+						Name: "e2e.yaml",
+						Ref:  "{{ .CommitSha }}",
+						Inputs: []*TriggerInput{
+							{Name: "E2E_FLOW", Value: "happy-flow"},
+							{Name: "releaseTag", Value: "{{ .Tag }}"},
+						},
+					},
+				},
 			}
 
-			_, _ = NewGlobalConfig("")
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
 
-			repoConfig, err := NewRepoConfig(mockPlatforms, "owner", "repository")
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
 			if err != nil {
 				t.Errorf("Error not expected: %#v", err)
 			}
 
+			// sources are covered by the organization config tests
+			repoConfig.Sources = nil
+
 			if diff := pretty.Compare(repoConfig, expectedRepoConfig); diff != "" {
 				t.Errorf("diff: (-got +want)\n%s", diff)
 			}
 		})
+
+	t.Run("should report errors if repo config file is invalid",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				Return(nil, platforms.ErrFileNotFound)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "").
+				DoAndReturn(
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return strings.NewReader(`enabled: false
+statuse:
+  - happy-flow`), nil
+					})
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+
+			if repoConfig.Enabled {
+				t.Errorf("Expected valid settings to be decoded")
+			}
+
+			expected := []string{`.grgate.yaml: line 2: unknown field "statuse"`}
+			if diff := pretty.Compare(repoConfig.Errors, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+
+	t.Run("should use default settings if repo config file can't be decoded",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				Return(nil, platforms.ErrFileNotFound)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "").
+				DoAndReturn(
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return strings.NewReader("tagRegexp: v.*\nstatuses:\n\t- happy-flow"), nil
+					})
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+
+			if repoConfig.TagRegexp != DefaultTagRegexp {
+				t.Errorf("Expected default settings to be used")
+			}
+
+			expected := []string{`.grgate.yaml: line 3: found character that cannot start any token`}
+			if diff := pretty.Compare(repoConfig.Errors, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+
+	t.Run("should merge the organization config with the repo config",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				DoAndReturn(
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return strings.NewReader(`dashboard:
+  author: org author
+  title: org title
+statuses:
+  - org-status
+tagRegexp: v.*`), nil
+					})
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "").
+				DoAndReturn(
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return strings.NewReader(`dashboard:
+  title: repo title
+statuses:
+  - repo-status`), nil
+					})
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+
+			expectedDashboard := &Dashboard{
+				Enabled:  DefaultDashboardEnabled,
+				Author:   "org author",
+				Title:    "repo title",
+				Template: DefaultDashboardTemplate,
+			}
+			if diff := pretty.Compare(repoConfig.Dashboard, expectedDashboard); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			if diff := pretty.Compare(repoConfig.Statuses, []string{"repo-status"}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			if repoConfig.TagRegexp != "v.*" {
+				t.Errorf("Expected tagRegexp from the organization config, got %s",
+					repoConfig.TagRegexp)
+			}
+
+			expectedSources := map[string]string{
+				"dashboard.enabled": SourceDefault,
+				"dashboard.author":  "owner/.github/grgate.yaml",
+				"dashboard.title":   DefaultRepoConfigPath,
+				"statuses":          DefaultRepoConfigPath,
+				"tagRegexp":         "owner/.github/grgate.yaml",
+			}
+			for key, expected := range expectedSources {
+				if result := repoConfig.Source(key); result != expected {
+					t.Errorf("Expected source of %s to be %s, got %s", key, expected, result)
+				}
+			}
+		})
+
+	t.Run("should report errors of the organization config",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				DoAndReturn(
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return strings.NewReader("tagRegexp: v.*\nstatuses:\n\t- org-status"), nil
+					})
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "").
+				DoAndReturn(
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return strings.NewReader(`statuses:
+  - repo-status`), nil
+					})
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+
+			if repoConfig.TagRegexp != DefaultTagRegexp {
+				t.Errorf("Expected default settings to be used")
+			}
+
+			if diff := pretty.Compare(repoConfig.Statuses, []string{"repo-status"}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			expected := []string{`owner/.github/grgate.yaml: line 3: found character that cannot start any token`}
+			if diff := pretty.Compare(repoConfig.Errors, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	t.Run("should read the repo config from the configured ref",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				Return(nil, platforms.ErrFileNotFound)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "release-1.x").
+				DoAndReturn(
+					func(_ string, _ string, _ string, _ string) (io.Reader, error) {
+						return strings.NewReader(`statuses:
+  - release-status`), nil
+					})
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			mainConfig.RepoConfigMode = RepoConfigModeRef
+			mainConfig.RepoConfigRef = "release-1.x"
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+
+			if diff := pretty.Compare(repoConfig.Statuses, []string{"release-status"}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	t.Run("should read the first repo config file found in the candidate paths",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			gomock.InOrder(
+				mockPlatforms.EXPECT().ReadFile("owner", "repository", ".github/grgate.yaml", "").
+					Return(nil, platforms.ErrFileNotFound),
+				mockPlatforms.EXPECT().ReadFile("owner", "repository", ".gitlab/grgate.json", "").
+					Return(strings.NewReader(`{
+  "statuses": ["json-status"],
+  "dashboard": {"title": "json title"}
+}`), nil),
+			)
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			mainConfig.OrgConfig = nil
+			mainConfig.RepoConfigPath = []string{".github/grgate.yaml",
+				".gitlab/grgate.json", ".grgate.yaml"}
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+
+			if diff := pretty.Compare(repoConfig.Statuses, []string{"json-status"}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			if repoConfig.Dashboard.Title != "json title" {
+				t.Errorf("Expected dashboard title from the json file, got %s",
+					repoConfig.Dashboard.Title)
+			}
+
+			if source := repoConfig.Source("statuses"); source != ".gitlab/grgate.json" {
+				t.Errorf("Expected source of statuses to be .gitlab/grgate.json, got %s",
+					source)
+			}
+		})
+
+	t.Run("should not read the next candidate path if a file can't be read",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".github/grgate.yaml", "").
+				Return(nil, errors.New("bad gateway"))
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			mainConfig.RepoConfigPath = []string{".github/grgate.yaml", ".grgate.yaml"}
+
+			_, _, err := NewLoader(mainConfig, zerolog.Nop()).ReadRepoConfigFile(
+				mockPlatforms, "owner", "repository", "")
+			expected := `couldn't read file ".github/grgate.yaml" from owner/repository: bad gateway`
+			if err == nil || err.Error() != expected {
+				t.Errorf("Expected error %q, got %#v", expected, err)
+			}
+		})
+
+	t.Run("should return an error instead of the defaults if a config file can't be read",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				Return(nil, platforms.ErrFileNotFound)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", DefaultRepoConfigPath, "").
+				Return(nil, errors.New("bad gateway"))
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err == nil {
+				t.Errorf("Expected error, got %#v", repoConfig)
+			}
+		})
+
+	t.Run("should return a file not found error if none of the candidate paths exist",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", gomock.Any(), "").
+				Return(nil, platforms.ErrFileNotFound).Times(2)
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			mainConfig.RepoConfigPath = []string{".github/grgate.yaml", ".grgate.yaml"}
+
+			_, _, err := NewLoader(mainConfig, zerolog.Nop()).ReadRepoConfigFile(
+				mockPlatforms, "owner", "repository", "")
+			if !errors.Is(err, platforms.ErrFileNotFound) {
+				t.Errorf("Expected file not found error, got %#v", err)
+			}
+		})
+
+	t.Run("should merge config files of different formats",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ReadFile("owner", DefaultOrgConfigRepository, DefaultOrgConfigPath, "").
+				Return(strings.NewReader(`dashboard:
+  author: org author
+tagRegexp: v.*`), nil)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.toml", "").
+				Return(strings.NewReader(`statuses = ["toml-status"]
+
+[dashboard]
+title = "toml title"`), nil)
+
+			_, mainConfig, _ := LoadGlobalConfig("", nil)
+
+			mainConfig.RepoConfigPath = []string{".grgate.toml"}
+
+			repoConfig, err := NewLoader(mainConfig, zerolog.Nop()).RepoConfig(mockPlatforms,
+				"owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+
+			if len(repoConfig.Errors) > 0 {
+				t.Errorf("Errors not expected: %#v", repoConfig.Errors)
+			}
+
+			expectedDashboard := &Dashboard{
+				Enabled:  DefaultDashboardEnabled,
+				Author:   "org author",
+				Title:    "toml title",
+				Template: DefaultDashboardTemplate,
+			}
+			if diff := pretty.Compare(repoConfig.Dashboard, expectedDashboard); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			if diff := pretty.Compare(repoConfig.Statuses, []string{"toml-status"}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			if repoConfig.TagRegexp != "v.*" {
+				t.Errorf("Expected tagRegexp from the organization config, got %s",
+					repoConfig.TagRegexp)
+			}
+		})
 }
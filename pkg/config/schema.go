@@ -0,0 +1,90 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// jsonSchemaDraft is the JSON Schema version of the generated schemas
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Regexp matching a Go duration, ie: 1h30m
+const durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// schemaEnums define the allowed values of fields, indexed by
+// "TypeName.FieldName"
+var schemaEnums = map[string][]string{
+	"MainConfig.Platform":       {string(GithubPlatform), string(GitlabPlatform)},
+	"MainConfig.RepoConfigMode": {RepoConfigModeDefaultBranch, RepoConfigModeRef, RepoConfigModeRelease},
+	"MetricQuery.Operator":      metricOperators,
+	"RepoConfig.StatusesFrom":   {StatusesFromBranchProtection},
+	"Signature.Target":          {"tag", "commit"},
+}
+
+// MainConfigJSONSchema returns the JSON Schema of the main config file
+func MainConfigJSONSchema() ([]byte, error) {
+	return jsonSchema(reflect.TypeOf(MainConfig{}), "GRGate config")
+}
+
+// RepoConfigJSONSchema returns the JSON Schema of the repository config file
+func RepoConfigJSONSchema() ([]byte, error) {
+	return jsonSchema(reflect.TypeOf(RepoConfig{}), "GRGate repository config")
+}
+
+func jsonSchema(t reflect.Type, title string) ([]byte, error) {
+	schema := typeSchema(t)
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = title
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the JSON Schema of a config type, struct properties are
+// named after their mapstructure tag
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]interface{}{
+			"type":    []string{"string", "integer"},
+			"pattern": durationPattern,
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("mapstructure")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			property := typeSchema(field.Type)
+			if enum, ok := schemaEnums[t.Name()+"."+field.Name]; ok {
+				property["enum"] = enum
+			}
+			properties[tag] = property
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem()),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+
+	return map[string]interface{}{"type": "string"}
+}
@@ -0,0 +1,37 @@
+//go:build unit
+
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	testCases := []struct {
+		path     string
+		generate func() ([]byte, error)
+	}{
+		{path: "../../schema/config.schema.json", generate: MainConfigJSONSchema},
+		{path: "../../schema/grgate.schema.json", generate: RepoConfigJSONSchema},
+	}
+
+	for _, testCase := range testCases {
+		t.Run("should match the published schema "+testCase.path, func(t *testing.T) {
+			expected, err := os.ReadFile(testCase.path)
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+
+			result, err := testCase.generate()
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+
+			if string(result) != strings.TrimSpace(string(expected)) {
+				t.Errorf("Schema %s is outdated, run make schema", testCase.path)
+			}
+		})
+	}
+}
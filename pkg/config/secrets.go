@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// pemPrefix is the prefix of a PEM encoded private key
+const pemPrefix = "-----BEGIN"
+
+// resolveSecrets read the secrets defined by a file path and decode the
+// inline Github private key. A secret can't be defined both inline and by a
+// file path
+func resolveSecrets(config *MainConfig) (err error) {
+	if config.Gitlab != nil {
+		if config.Gitlab.Token, err = readSecretFile("gitlab.token",
+			config.Gitlab.Token, config.Gitlab.TokenFile); err != nil {
+			return
+		}
+	}
+
+	if config.ExternalSecret, err = readSecretFile("externalSecret",
+		config.ExternalSecret, config.ExternalSecretFile); err != nil {
+		return
+	}
+
+	if config.Server != nil {
+		if config.Server.WebhookSecret, err = readSecretFile("server.webhookSecret",
+			config.Server.WebhookSecret, config.Server.WebhookSecretFile); err != nil {
+			return
+		}
+	}
+
+	if config.Github != nil && config.Github.PrivateKey != "" {
+		if config.Github.PrivateKeyPath != "" {
+			return fmt.Errorf("github.privateKey and github.privateKeyPath are mutually exclusive")
+		}
+		if !strings.HasPrefix(strings.TrimSpace(config.Github.PrivateKey), pemPrefix) {
+			key, err := base64.StdEncoding.DecodeString(
+				strings.TrimSpace(config.Github.PrivateKey))
+			if err != nil {
+				return fmt.Errorf("github.privateKey: invalid base64 encoded key: %s", err)
+			}
+			config.Github.PrivateKey = string(key)
+		}
+	}
+
+	return nil
+}
+
+// readSecretFile returns the content of the secret file without surrounding
+// whitespaces, or the value if no file is defined
+func readSecretFile(key, value, path string) (string, error) {
+	if path == "" {
+		return value, nil
+	}
+
+	if value != "" {
+		return "", fmt.Errorf("%s and %sFile are mutually exclusive", key, key)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%sFile: %s", key, err)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
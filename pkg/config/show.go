@@ -0,0 +1,162 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Setting define the effective value of a repository config setting and
+// where it came from
+type Setting struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// Settings returns the effective settings of the repository config in the
+// order of the config fields. Lists are returned as a single setting since
+// they are replaced and not merged between config files
+func (c *RepoConfig) Settings() (settings []*Setting) {
+	walkSettings(reflect.ValueOf(c), nil, func(path []string, value reflect.Value) {
+		key := strings.Join(path, ".")
+		settings = append(settings, &Setting{
+			Key:    key,
+			Value:  plainValue(value),
+			Source: c.Source(key),
+		})
+	})
+	return
+}
+
+// YAMLNode returns the repository config as a yaml document where each
+// setting is annotated with its source
+func (c *RepoConfig) YAMLNode() (*yaml.Node, error) {
+	return c.yamlNode(reflect.ValueOf(c), nil)
+}
+
+func (c *RepoConfig) yamlNode(value reflect.Value, path []string) (*yaml.Node, error) {
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], tag)
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: tag}
+
+		var child *yaml.Node
+		if isSettingGroup(field) {
+			var err error
+			if child, err = c.yamlNode(field, fieldPath); err != nil {
+				return nil, err
+			}
+		} else {
+			child = &yaml.Node{}
+			if err := child.Encode(plainValue(field)); err != nil {
+				return nil, err
+			}
+
+			// comments of block values are rendered after the key
+			source := c.Source(strings.Join(fieldPath, "."))
+			if child.Kind == yaml.ScalarNode || len(child.Content) == 0 {
+				child.LineComment = source
+			} else {
+				key.LineComment = source
+			}
+		}
+
+		node.Content = append(node.Content, key, child)
+	}
+
+	return node, nil
+}
+
+// walkSettings call fn for each setting of the config, nested structs are
+// walked and their fields prefixed by the struct key
+func walkSettings(value reflect.Value, path []string, fn func([]string, reflect.Value)) {
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], tag)
+		if isSettingGroup(field) {
+			walkSettings(field, fieldPath, fn)
+			continue
+		}
+
+		fn(fieldPath, field)
+	}
+}
+
+// isSettingGroup returns true if the value is a struct which contains
+// settings
+func isSettingGroup(value reflect.Value) bool {
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	return value.Kind() == reflect.Struct
+}
+
+// plainValue convert a config value to maps, lists and scalars keyed by
+// their mapstructure tag so that they are encoded as in config files
+func plainValue(value reflect.Value) interface{} {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(value.Int()).String()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		t := value.Type()
+		for i := 0; i < t.NumField(); i++ {
+			tag := t.Field(i).Tag.Get("mapstructure")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			m[tag] = plainValue(value.Field(i))
+		}
+		return m
+	case reflect.Slice:
+		list := make([]interface{}, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			list = append(list, plainValue(value.Index(i)))
+		}
+		return list
+	}
+
+	return value.Interface()
+}
@@ -0,0 +1,102 @@
+//go:build unit
+
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRepoConfigShow(t *testing.T) {
+	repoConfig := &RepoConfig{
+		Enabled: true,
+		Dashboard: &Dashboard{
+			Enabled: true,
+			Title:   "repo title",
+		},
+		Plugins: []*Plugin{
+			{Name: "lint", Command: "lint.sh", Timeout: time.Minute},
+		},
+		Statuses: []string{"e2e"},
+		Sources: map[string]string{
+			"dashboard.title": ".grgate.yaml",
+			"plugins":         "owner/.github/grgate.yaml",
+			"statuses":        ".grgate.yaml",
+		},
+	}
+
+	t.Run("should return the settings with their source", func(t *testing.T) {
+		expected := []*Setting{
+			{Key: "enabled", Value: true, Source: SourceDefault},
+			{Key: "dashboard.enabled", Value: true, Source: SourceDefault},
+			{Key: "dashboard.author", Value: "", Source: SourceDefault},
+			{Key: "dashboard.title", Value: "repo title", Source: ".grgate.yaml"},
+			{Key: "dashboard.template", Value: "", Source: SourceDefault},
+			{Key: "dependsOn", Value: []interface{}{}, Source: SourceDefault},
+			{Key: "dependsOnRecheckInterval", Value: "0s", Source: SourceDefault},
+			{Key: "plugins", Value: []interface{}{
+				map[string]interface{}{
+					"name":    "lint",
+					"command": "lint.sh",
+					"args":    []interface{}{},
+					"env":     []interface{}{},
+					"timeout": "1m0s",
+				},
+			}, Source: "owner/.github/grgate.yaml"},
+			{Key: "pluginsRecheckInterval", Value: "0s", Source: SourceDefault},
+			{Key: "statuses", Value: []interface{}{"e2e"}, Source: ".grgate.yaml"},
+			{Key: "statusesFrom", Value: "", Source: SourceDefault},
+			{Key: "statusRules", Value: []interface{}{}, Source: SourceDefault},
+			{Key: "tagRegexp", Value: "", Source: SourceDefault},
+			{Key: "triggers", Value: []interface{}{}, Source: SourceDefault},
+		}
+
+		if diff := pretty.Compare(repoConfig.Settings(), expected); diff != "" {
+			t.Errorf("diff: (-got +want)\n%s", diff)
+		}
+	})
+
+	t.Run("should annotate the yaml document with the sources", func(t *testing.T) {
+		node, err := repoConfig.YAMLNode()
+		if err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(node); err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+
+		expected := `enabled: true # default
+dashboard:
+  enabled: true # default
+  author: "" # default
+  title: repo title # .grgate.yaml
+  template: "" # default
+dependsOn: [] # default
+dependsOnRecheckInterval: 0s # default
+plugins: # owner/.github/grgate.yaml
+  - args: []
+    command: lint.sh
+    env: []
+    name: lint
+    timeout: 1m0s
+pluginsRecheckInterval: 0s # default
+statuses: # .grgate.yaml
+  - e2e
+statusesFrom: "" # default
+statusRules: [] # default
+tagRegexp: "" # default
+triggers: [] # default
+`
+		if diff := pretty.Compare(buf.String(), expected); diff != "" {
+			t.Errorf("diff: (-got +want)\n%s", diff)
+		}
+	})
+}
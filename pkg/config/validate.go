@@ -0,0 +1,547 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError define an error found in a config file, Line is 0 when the
+// error can't be associated to a line
+type ValidationError struct {
+	Line    int
+	Message string
+}
+
+// Error returns the error prefixed by its line number
+func (e *ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// Regexp matching the line number of yaml parse errors
+var yamlErrorLineRegexp = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// Operators supported by metric queries
+var metricOperators = []string{"<", "<=", ">", ">=", "==", "=", "!="}
+
+// Regexp matching the name of an environment variable passed to plugins
+var pluginEnvNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Environment variables plugins can't define, they are either set by GRGate or
+// change the code loaded by the plugin and its interpreter
+var reservedPluginEnv = []string{
+	"BASH_ENV", "BASHOPTS", "CDPATH", "ENV", "GCONV_PATH", "HOME", "IFS",
+	"NODE_OPTIONS", "PATH", "PERL5LIB", "PERL5OPT", "PS4", "PYTHONPATH",
+	"PYTHONSTARTUP", "RUBYLIB", "RUBYOPT", "SHELLOPTS",
+}
+
+// Prefixes of the environment variables plugins can't define
+var reservedPluginEnvPrefixes = []string{"BASH_FUNC_", "DYLD_", "LD_"}
+
+// CheckPluginEnv returns an error if the environment variable name is invalid
+// or reserved, in which case it can't be passed to a plugin
+func CheckPluginEnv(name string) error {
+	if !pluginEnvNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid environment variable name \"%s\"", name)
+	}
+
+	upper := strings.ToUpper(name)
+	if contains(reservedPluginEnv, upper) {
+		return fmt.Errorf("environment variable %s is reserved", name)
+	}
+	for _, prefix := range reservedPluginEnvPrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return fmt.Errorf("environment variable %s is reserved", name)
+		}
+	}
+
+	return nil
+}
+
+// validator walk a yaml document and collect validation errors
+type validator struct {
+	root   *yaml.Node
+	errors []*ValidationError
+}
+
+// ValidateRepoConfig validate the content of a repository config file in the
+// given format. Unknown keys, values of the wrong type, invalid regexps,
+// templates and unsupported values are reported
+func ValidateRepoConfig(content []byte, format string) []*ValidationError {
+	var config *RepoConfig
+	val, ok := newValidator(content, format, reflect.TypeOf(config), &config)
+	if !ok {
+		return val.errors
+	}
+
+	if config != nil {
+		val.validateRepoConfig(config)
+	}
+
+	return val.errors
+}
+
+// ValidateMainConfig validate the content of the main config file in the
+// given format, the globals section is validated as a repository config
+func ValidateMainConfig(content []byte, format string) []*ValidationError {
+	var config *MainConfig
+	val, ok := newValidator(content, format, reflect.TypeOf(config), &config)
+	if !ok {
+		return val.errors
+	}
+
+	if config == nil {
+		return val.errors
+	}
+
+	if config.Platform != nil && *config.Platform != GithubPlatform &&
+		*config.Platform != GitlabPlatform {
+		val.addf([]string{"platform"}, "unsupported platform \"%s\", must be one of: %s, %s",
+			*config.Platform, GithubPlatform, GitlabPlatform)
+	}
+
+	switch config.RepoConfigMode {
+	case "", RepoConfigModeDefaultBranch, RepoConfigModeRelease:
+	case RepoConfigModeRef:
+		if config.RepoConfigRef == "" {
+			val.addf([]string{"repoConfigMode"},
+				"repoConfigRef is required when repoConfigMode is \"%s\"",
+				RepoConfigModeRef)
+		}
+	default:
+		val.addf([]string{"repoConfigMode"},
+			"unsupported repoConfigMode \"%s\", must be one of: %s, %s, %s",
+			config.RepoConfigMode, RepoConfigModeDefaultBranch, RepoConfigModeRef,
+			RepoConfigModeRelease)
+	}
+
+	if config.Repositories != nil {
+		for _, list := range []struct {
+			key      string
+			patterns []string
+		}{
+			{"include", config.Repositories.Include},
+			{"exclude", config.Repositories.Exclude},
+		} {
+			key := list.key
+			for i, pattern := range list.patterns {
+				pattern = strings.TrimPrefix(pattern, RepositoryTopicPrefix)
+				if _, err := path.Match(pattern, ""); err != nil {
+					val.addf([]string{"repositories", key, strconv.Itoa(i)},
+						"repositories.%s: invalid pattern \"%s\": %s", key, pattern, err)
+				}
+			}
+		}
+	}
+
+	if config.Globals != nil {
+		val.validateRepoConfig(config.Globals, "globals")
+
+		platform := DefaultPlatform
+		if config.Platform != nil {
+			platform = *config.Platform
+		}
+		for _, setting := range unsupportedSettings(platform, config.Globals) {
+			val.add(append([]string{"globals"}, setting.key...), setting.message)
+		}
+	}
+
+	return val.errors
+}
+
+// newValidator parse the content, check the keys and types against the type
+// of config and decode the content into config. It returns false if the
+// content couldn't be parsed or decoded
+func newValidator(content []byte, format string, t reflect.Type,
+	config interface{}) (*validator, bool) {
+	val := &validator{root: &yaml.Node{}}
+
+	root, parseErr := parseNode(content, format)
+	if parseErr != nil {
+		val.errors = append(val.errors, parseErr)
+		return val, false
+	}
+	val.root = root
+
+	if len(val.root.Content) == 0 {
+		return val, true
+	}
+
+	val.walk(val.root.Content[0], t, nil)
+
+	// values are still decoded when unknown keys are found so that all the
+	// errors are reported at once, decoding errors are only reported if the
+	// types didn't already fail the validation
+	v := viper.New()
+	v.SetConfigType(format)
+	err := v.ReadConfig(bytes.NewReader(content))
+	if err == nil {
+		err = v.Unmarshal(config)
+	}
+	if err != nil {
+		if len(val.errors) == 0 {
+			val.errors = append(val.errors, &ValidationError{Message: err.Error()})
+		}
+		return val, false
+	}
+
+	return val, true
+}
+
+// yamlError convert a yaml parse error to a validation error
+func yamlError(err error) *ValidationError {
+	match := yamlErrorLineRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &ValidationError{Line: line, Message: match[2]}
+}
+
+// add record a validation error for the value at path
+func (val *validator) add(path []string, message string) {
+	val.errors = append(val.errors, &ValidationError{
+		Line:    val.line(path),
+		Message: message,
+	})
+}
+
+// addf record a formatted validation error for the value at path
+func (val *validator) addf(path []string, format string, a ...interface{}) {
+	val.add(path, fmt.Sprintf(format, a...))
+}
+
+// line returns the line of the value at path, or of its closest parent found
+// in the document
+func (val *validator) line(path []string) int {
+	if len(val.root.Content) == 0 {
+		return 0
+	}
+
+	node := val.root.Content[0]
+	line := node.Line
+
+	for _, key := range path {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if strings.EqualFold(node.Content[i].Value, key) {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(key); err == nil && i < len(node.Content) {
+				next = node.Content[i]
+			}
+		}
+		if next == nil {
+			return line
+		}
+		node = next
+		line = node.Line
+	}
+
+	return line
+}
+
+// walk check that the yaml node match the type, keys are matched against the
+// mapstructure tags case insensitively as viper does
+func (val *validator) walk(node *yaml.Node, t reflect.Type, path []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	name := strings.Join(path, ".")
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		if node.Kind != yaml.ScalarNode {
+			val.add(path, fmt.Sprintf("%s must be a duration", name))
+			return
+		}
+		if node.Tag != "!!int" {
+			if _, err := time.ParseDuration(node.Value); err != nil {
+				val.add(path, fmt.Sprintf("%s: invalid duration \"%s\"", name, node.Value))
+			}
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			val.add(path, fmt.Sprintf("%s must be a map", name))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			field, ok := findField(t, key.Value)
+			if !ok {
+				val.errors = append(val.errors, &ValidationError{
+					Line:    key.Line,
+					Message: fmt.Sprintf("unknown field \"%s\"", strings.Join(append(path, key.Value), ".")),
+				})
+				continue
+			}
+			val.walk(node.Content[i+1], field.Type, append(path[:len(path):len(path)], key.Value))
+		}
+	case reflect.Slice:
+		if node.Kind == yaml.ScalarNode && t.Elem().Kind() == reflect.String {
+			// a single value is decoded as a list of one element
+			return
+		}
+		if node.Kind != yaml.SequenceNode {
+			val.add(path, fmt.Sprintf("%s must be a list", name))
+			return
+		}
+		for i, item := range node.Content {
+			val.walk(item, t.Elem(), append(path[:len(path):len(path)], strconv.Itoa(i)))
+		}
+	case reflect.Bool:
+		if node.Kind != yaml.ScalarNode {
+			val.add(path, fmt.Sprintf("%s must be a boolean", name))
+			return
+		}
+		if _, err := strconv.ParseBool(node.Value); err != nil {
+			val.add(path, fmt.Sprintf("%s must be a boolean", name))
+		}
+	case reflect.Int, reflect.Int64:
+		if node.Kind != yaml.ScalarNode {
+			val.add(path, fmt.Sprintf("%s must be an integer", name))
+			return
+		}
+		if _, err := strconv.ParseInt(node.Value, 0, 64); err != nil {
+			val.add(path, fmt.Sprintf("%s must be an integer", name))
+		}
+	case reflect.Float64:
+		if node.Kind != yaml.ScalarNode {
+			val.add(path, fmt.Sprintf("%s must be a number", name))
+			return
+		}
+		if _, err := strconv.ParseFloat(node.Value, 64); err != nil {
+			val.add(path, fmt.Sprintf("%s must be a number", name))
+		}
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			val.add(path, fmt.Sprintf("%s must be a string", name))
+		}
+	}
+}
+
+// findField returns the struct field matching the mapstructure key
+func findField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if strings.EqualFold(tag, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// validateTemplate record an error if the value at path is not a valid
+// template
+func (val *validator) validateTemplate(path []string, tpl string) {
+	if tpl == "" {
+		return
+	}
+	if _, err := template.New("tpl").Parse(tpl); err != nil {
+		val.addf(path, "%s: invalid template: %s", strings.Join(path, "."), err)
+	}
+}
+
+// validateRepoConfig validate the values of a repository config, prefix is
+// the path of the repository config in the document
+func (val *validator) validateRepoConfig(config *RepoConfig, prefix ...string) {
+	path := func(keys ...interface{}) []string {
+		p := append([]string{}, prefix...)
+		for _, key := range keys {
+			p = append(p, fmt.Sprint(key))
+		}
+		return p
+	}
+
+	if config.TagRegexp != "" {
+		if _, err := regexp.Compile(config.TagRegexp); err != nil {
+			val.addf(path("tagRegexp"), "tagRegexp: invalid regexp: %s", err)
+		}
+	}
+
+	if config.StatusesFrom != "" && config.StatusesFrom != StatusesFromBranchProtection {
+		val.addf(path("statusesFrom"), "unsupported statusesFrom \"%s\", must be: %s",
+			config.StatusesFrom, StatusesFromBranchProtection)
+	}
+
+	for i, rule := range config.StatusRules {
+		if rule.Retries < 0 {
+			val.addf(path("statusRules", i, "retries"),
+				"statusRules: retries of %s must be positive", rule.Name)
+		}
+	}
+
+	if config.Dashboard != nil {
+		val.validateTemplate(path("dashboard", "template"), config.Dashboard.Template)
+	}
+
+	if config.ReleaseNote != nil {
+		val.validateTemplate(path("releaseNote", "template"), config.ReleaseNote.Template)
+	}
+
+	if config.Metrics != nil {
+		for i, query := range config.Metrics.Queries {
+			val.validateTemplate(path("metrics", "queries", i, "query"), query.Query)
+			if !contains(metricOperators, query.Operator) {
+				val.addf(path("metrics", "queries", i, "operator"),
+					"unsupported operator \"%s\" in metric query %s, must be one of: %s",
+					query.Operator, query.Name, strings.Join(metricOperators, ", "))
+			}
+		}
+	}
+
+	for i, plugin := range config.Plugins {
+		for j, env := range plugin.Env {
+			if err := CheckPluginEnv(env.Name); err != nil {
+				val.addf(path("plugins", i, "env", j, "name"), "plugins: %s in %s",
+					err, plugin.Name)
+			}
+		}
+	}
+
+	if config.Quota != nil && config.Quota.Limit < 0 {
+		val.add(path("quota", "limit"), "quota: limit must be positive")
+	}
+
+	if config.Signature != nil && config.Signature.Target != "" &&
+		config.Signature.Target != "tag" && config.Signature.Target != "commit" {
+		val.addf(path("signature", "target"),
+			"unsupported signature target \"%s\", must be one of: tag, commit",
+			config.Signature.Target)
+	}
+
+	if config.Signature != nil {
+		for _, file := range []struct {
+			key  string
+			path string
+		}{
+			{"keyring", config.Signature.Keyring},
+			{"allowedSigners", config.Signature.AllowedSigners},
+		} {
+			if file.path != "" && !filepath.IsLocal(file.path) {
+				val.addf(path("signature", file.key),
+					"signature.%s: \"%s\" must be a path relative to the signatures directory",
+					file.key, file.path)
+			}
+		}
+
+		for i, keyID := range config.Signature.AllowedKeyIDs {
+			if !IsValidKeyID(keyID) {
+				val.addf(path("signature", "allowedKeyIDs", i),
+					"signature.allowedKeyIDs: \"%s\" must be a long key ID of 16 hexadecimal characters or a fingerprint",
+					keyID)
+			}
+		}
+	}
+
+	for i, trigger := range config.Triggers {
+		val.validateTemplate(path("triggers", i, "ref"), trigger.Ref)
+		for j, input := range trigger.Inputs {
+			val.validateTemplate(path("triggers", i, "inputs", j, "value"), input.Value)
+		}
+	}
+}
+
+// keyIDRegexp match a GPG long key ID or a v4 or v5 key fingerprint, short key
+// IDs are rejected since colliding keys are easily generated
+var keyIDRegexp = regexp.MustCompile(`^([0-9A-Fa-f]{16}|[0-9A-Fa-f]{40}|[0-9A-Fa-f]{64})$`)
+
+// IsValidKeyID returns true if the key ID is a GPG long key ID or a key
+// fingerprint
+func IsValidKeyID(keyID string) bool {
+	return keyIDRegexp.MatchString(keyID)
+}
+
+// unsupportedSetting define a setting which isn't supported by the platform,
+// key is the path of the setting in the repository config
+type unsupportedSetting struct {
+	key     []string
+	message string
+}
+
+// unsupportedSettings returns the settings of the repository config which
+// aren't supported by the platform. Unsupported status source fields never
+// match a status, the required status would never succeed
+func unsupportedSettings(platform PlatformType, config *RepoConfig) (settings []*unsupportedSetting) {
+	if platform == GitlabPlatform && config.Signature != nil &&
+		config.Signature.Enabled && config.Signature.Target == "tag" {
+		settings = append(settings, &unsupportedSetting{
+			key:     []string{"signature", "target"},
+			message: "signature: target tag is not supported on gitlab, must be: commit",
+		})
+	}
+
+	for i, rule := range config.StatusRules {
+		if rule.Source == nil {
+			continue
+		}
+
+		var fields []string
+		switch platform {
+		case GithubPlatform:
+			if rule.Source.Creator != "" {
+				fields = append(fields, "creator")
+			}
+			if rule.Source.PipelineSource != "" {
+				fields = append(fields, "pipelineSource")
+			}
+		case GitlabPlatform:
+			if rule.Source.App != "" {
+				fields = append(fields, "app")
+			}
+		}
+
+		for _, field := range fields {
+			settings = append(settings, &unsupportedSetting{
+				key: []string{"statusRules", strconv.Itoa(i), "source", field},
+				message: fmt.Sprintf("statusRules: source.%s of %s is not supported on %s",
+					field, rule.Name, platform),
+			})
+		}
+	}
+	return
+}
+
+// contains returns true if the list contains the value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
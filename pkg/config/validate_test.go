@@ -0,0 +1,270 @@
+//go:build unit
+
+package config
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestValidateRepoConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		content  string
+		expected []string
+	}{
+		{
+			name: "should accept a valid config",
+			content: `enabled: true
+statuses:
+  - e2e happy flow
+tagRegexp: ^v\d+\.\d+\.\d+$
+statusRules:
+  - name: e2e happy flow
+    retries: 2
+    source:
+      app: 1234
+timeout:
+  duration: 72h
+`,
+		},
+		{
+			name:    "should accept an empty config",
+			content: ``,
+		},
+		{
+			name: "should report unknown keys with their line",
+			content: `enabled: true
+statuse:
+  - e2e happy flow
+dashboard:
+  titel: some title
+`,
+			expected: []string{
+				`line 2: unknown field "statuse"`,
+				`line 5: unknown field "dashboard.titel"`,
+			},
+		},
+		{
+			name: "should report values of the wrong type",
+			content: `enabled: maybe
+quota:
+  limit: lots
+timeout:
+  duration: 3 days
+statuses:
+  name: e2e
+`,
+			expected: []string{
+				`line 1: enabled must be a boolean`,
+				`line 3: quota.limit must be an integer`,
+				`line 5: timeout.duration: invalid duration "3 days"`,
+				`line 7: statuses must be a list`,
+			},
+		},
+		{
+			name: "should report invalid regexps, templates and values",
+			content: `tagRegexp: v([
+statusesFrom: protection
+releaseNote:
+  template: "{{ .Statuses "
+signature:
+  target: branch
+`,
+			expected: []string{
+				"line 1: tagRegexp: invalid regexp: error parsing regexp: missing closing ]: `[`",
+				`line 2: unsupported statusesFrom "protection", must be: branchProtection`,
+				`line 4: releaseNote.template: invalid template: template: tpl:1: unclosed action`,
+				`line 6: unsupported signature target "branch", must be one of: tag, commit`,
+			},
+		},
+		{
+			name: "should report signature files outside the signatures directory",
+			content: `signature:
+  keyring: /etc/passwd
+  allowedSigners: ../allowed_signers
+`,
+			expected: []string{
+				`line 2: signature.keyring: "/etc/passwd" must be a path relative to the signatures directory`,
+				`line 3: signature.allowedSigners: "../allowed_signers" must be a path relative to the signatures directory`,
+			},
+		},
+		{
+			name: "should report short signature key IDs",
+			content: `signature:
+  allowedKeyIDs:
+    - 3AFDEB23
+    - 4AEE18F83AFDEB23
+    - 0D69E11F12BDBA077B3726AB4AEE18F83AFDEB23
+`,
+			expected: []string{
+				`line 3: signature.allowedKeyIDs: "3AFDEB23" must be a long key ID of 16 hexadecimal characters or a fingerprint`,
+			},
+		},
+		{
+			name: "should report reserved plugin environment variables",
+			content: `plugins:
+  - name: scan
+    command: scan
+    env:
+      - name: LD_PRELOAD
+        value: /tmp/lib.so
+      - name: Path
+        value: /tmp
+      - name: SCAN_LEVEL
+        value: high
+`,
+			expected: []string{
+				`line 5: plugins: environment variable LD_PRELOAD is reserved in scan`,
+				`line 7: plugins: environment variable Path is reserved in scan`,
+			},
+		},
+		{
+			name:     "should report yaml syntax errors",
+			content:  "statuses: [e2e\n",
+			expected: []string{`line 1: did not find expected ',' or ']'`},
+		},
+		{
+			name:   "should report errors of json files with their line",
+			format: FormatJSON,
+			content: `{
+  "enabled": true,
+  "statuse": ["e2e happy flow"],
+  "timeout": {"duration": "3 days"}
+}
+`,
+			expected: []string{
+				`line 3: unknown field "statuse"`,
+				`line 4: timeout.duration: invalid duration "3 days"`,
+			},
+		},
+		{
+			name:   "should accept a valid toml config",
+			format: FormatTOML,
+			content: `enabled = true
+statuses = ["e2e happy flow"]
+tagRegexp = '^v\d+\.\d+\.\d+$'
+
+[timeout]
+duration = "72h"
+`,
+		},
+		{
+			name:   "should report errors of toml files",
+			format: FormatTOML,
+			content: `statuse = ["e2e happy flow"]
+
+[quota]
+limit = "lots"
+`,
+			// toml documents are converted to yaml with their keys sorted
+			expected: []string{
+				`quota.limit must be an integer`,
+				`unknown field "statuse"`,
+			},
+		},
+		{
+			name:     "should report toml syntax errors",
+			format:   FormatTOML,
+			content:  "enabled = true\nstatuses = [\"e2e\"\n",
+			expected: []string{`line 3: toml: expected character ] but the document ended here`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var result []string
+			format := testCase.format
+			if format == "" {
+				format = FormatYAML
+			}
+			for _, e := range ValidateRepoConfig([]byte(testCase.content), format) {
+				result = append(result, e.Error())
+			}
+			if diff := pretty.Compare(result, testCase.expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateMainConfig(t *testing.T) {
+	content := `platform: bitbucket
+globals:
+  tagRegexp: v([
+  statuss:
+    - e2e
+repositories:
+  include:
+    - fikaworks/*
+    - topic:[release
+server:
+  listenAddress: 0.0.0.0:8080
+repoConfigMode: ref
+`
+
+	var result []string
+	for _, e := range ValidateMainConfig([]byte(content), FormatYAML) {
+		result = append(result, e.Error())
+	}
+
+	expected := []string{
+		`line 4: unknown field "globals.statuss"`,
+		`line 1: unsupported platform "bitbucket", must be one of: github, gitlab`,
+		`line 12: repoConfigRef is required when repoConfigMode is "ref"`,
+		`line 9: repositories.include: invalid pattern "[release": syntax error in pattern`,
+		"line 3: tagRegexp: invalid regexp: error parsing regexp: missing closing ]: `[`",
+	}
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+}
+
+func TestUnsupportedSettings(t *testing.T) {
+	config := &RepoConfig{
+		StatusRules: []*StatusRule{
+			{Name: "e2e", Source: &StatusSource{App: "ci-app"}},
+			{Name: "lint", Source: &StatusSource{Creator: "bot", PipelineSource: "push"}},
+			{Name: "unit"},
+		},
+	}
+
+	t.Run("should reject the Gitlab only fields on Github", func(t *testing.T) {
+		expected := []string{
+			"statusRules: source.creator of lint is not supported on github",
+			"statusRules: source.pipelineSource of lint is not supported on github",
+		}
+		var result []string
+		for _, setting := range unsupportedSettings(GithubPlatform, config) {
+			result = append(result, setting.message)
+		}
+		if diff := pretty.Compare(result, expected); diff != "" {
+			t.Errorf("diff: (-got +want)\n%s", diff)
+		}
+	})
+
+	t.Run("should reject the Github only fields in the globals on Gitlab",
+		func(t *testing.T) {
+			content := `platform: gitlab
+globals:
+  statusRules:
+    - name: e2e
+      source:
+        app: ci-app
+`
+
+			var result []string
+			for _, e := range ValidateMainConfig([]byte(content), FormatYAML) {
+				result = append(result, e.Error())
+			}
+
+			expected := []string{
+				"line 6: statusRules: source.app of e2e is not supported on gitlab",
+			}
+			if diff := pretty.Compare(result, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
@@ -2,22 +2,35 @@ package platforms
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v43/github"
+	"github.com/rs/zerolog/log"
 )
 
 const (
 	// number of items per page to retrieve via the Github API
 	githubPerPage int = 100
+
+	// slug of the Github app creating the check runs of Github Actions jobs
+	githubActionsAppSlug string = "github-actions"
 )
 
+// Regexp matching a full commit SHA, used to differentiate a release target
+// commit from a target branch
+var commitShaRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
 // GithubConfig hold the Github configuration
 type GithubConfig struct {
 	AppID          int64
 	InstallationID int64
+	PrivateKey     []byte
 	PrivateKeyPath string
 }
 
@@ -31,8 +44,14 @@ type githubPlatform struct {
 func NewGithub(config *GithubConfig) (platform Platform, err error) {
 	ctx := context.Background()
 
-	itr, err := ghinstallation.NewKeyFromFile(http.DefaultTransport,
-		config.AppID, config.InstallationID, config.PrivateKeyPath)
+	var itr *ghinstallation.Transport
+	if len(config.PrivateKey) > 0 {
+		itr, err = ghinstallation.New(http.DefaultTransport, config.AppID,
+			config.InstallationID, config.PrivateKey)
+	} else {
+		itr, err = ghinstallation.NewKeyFromFile(http.DefaultTransport,
+			config.AppID, config.InstallationID, config.PrivateKeyPath)
+	}
 	if err != nil {
 		return
 	}
@@ -46,10 +65,25 @@ func NewGithub(config *GithubConfig) (platform Platform, err error) {
 	return
 }
 
-// ReadFile retrieve file located at the provided path in a given Github repository
-func (p *githubPlatform) ReadFile(owner, repository, path string) (content io.Reader, err error) {
-	content, _, err = p.client.Repositories.DownloadContents(p.context, owner,
-		repository, path, nil)
+// ReadFile retrieve file located at the provided path in a given Github
+// repository, the file is read from the default branch if ref is empty.
+// ErrFileNotFound is returned if the file doesn't exist
+func (p *githubPlatform) ReadFile(owner, repository, path, ref string) (content io.Reader, err error) {
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+	content, resp, err := p.client.Repositories.DownloadContents(p.context, owner,
+		repository, path, opts)
+	if err != nil {
+		// the parent directory is listed to find the file, a successful
+		// response means that the file is not part of the directory
+		if resp != nil && resp.Response != nil &&
+			(resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusOK) {
+			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, err)
+		}
+		return nil, err
+	}
 	return
 }
 
@@ -82,6 +116,16 @@ func (p *githubPlatform) ListReleases(owner, repository string) (releases []*Rel
 				releaseNote = *release.Body
 			}
 
+			var publishedAt time.Time
+			if !draft && release.PublishedAt != nil {
+				publishedAt = release.PublishedAt.Time
+			}
+
+			var createdAt time.Time
+			if release.CreatedAt != nil {
+				createdAt = release.CreatedAt.Time
+			}
+
 			// TODO: if target commitish is branch, then get lastest commit from
 			// branch
 			releases = append(releases, &Release{
@@ -92,6 +136,8 @@ func (p *githubPlatform) ListReleases(owner, repository string) (releases []*Rel
 				ReleaseNote: releaseNote,
 				Tag:         tag,
 				Draft:       draft,
+				PublishedAt: publishedAt,
+				CreatedAt:   createdAt,
 			})
 		}
 
@@ -160,9 +206,72 @@ func (p *githubPlatform) PublishRelease(owner, repository string, release *Relea
 	return
 }
 
-// CheckAllStatusSucceeded checks that all the provided statuses succeeded
+// UnpublishRelease revert a published release to draft and update its
+// release note
+func (p *githubPlatform) UnpublishRelease(owner, repository string, release *Release) (unpublished bool, err error) {
+	r, _, err := p.client.Repositories.GetRelease(p.context, owner, repository,
+		release.ID.(int64))
+	if err != nil {
+		return
+	}
+
+	r.Draft = github.Bool(true)
+	r.Body = github.String(release.ReleaseNote)
+
+	_, _, err = p.client.Repositories.EditRelease(p.context, owner, repository,
+		release.ID.(int64), r)
+	if err != nil {
+		return
+	}
+
+	unpublished = true
+	return
+}
+
+// ListReleaseAssets returns the assets attached to a release
+func (p *githubPlatform) ListReleaseAssets(owner, repository string, release *Release) (assets []*ReleaseAsset, err error) {
+	opts := &github.ListOptions{
+		Page:    0,
+		PerPage: githubPerPage,
+	}
+
+	for {
+		assetList, resp, err := p.client.Repositories.ListReleaseAssets(p.context,
+			owner, repository, release.ID.(int64), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, asset := range assetList {
+			assets = append(assets, &ReleaseAsset{
+				ID:   *asset.ID,
+				Name: *asset.Name,
+				URL:  asset.GetBrowserDownloadURL(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return assets, err
+}
+
+// DownloadReleaseAsset returns the content of a release asset, the caller is
+// responsible for closing it
+func (p *githubPlatform) DownloadReleaseAsset(owner, repository string, asset *ReleaseAsset) (content io.ReadCloser, err error) {
+	content, _, err = p.client.Repositories.DownloadReleaseAsset(p.context, owner,
+		repository, asset.ID.(int64), assetClient)
+	return
+}
+
+// CheckAllStatusSucceeded checks that all the provided statuses succeeded,
+// check runs which do not come from the trusted source of a status are ignored
 func (p *githubPlatform) CheckAllStatusSucceeded(owner, repository,
-	commitSha string, statuses []string) (succeeded bool, err error) {
+	commitSha string, statuses []string, sources map[string]*StatusSource) (succeeded bool, err error) {
 	if len(statuses) == 0 {
 		return true, nil
 	}
@@ -185,6 +294,7 @@ func (p *githubPlatform) CheckAllStatusSucceeded(owner, repository,
 		for _, check := range getCheckRun.CheckRuns {
 			for _, status := range statuses {
 				if *check.Name == status &&
+					isGithubCheckRunFromSource(check, sources[status]) &&
 					check.Status != nil &&
 					*check.Status == completedStatusValue &&
 					check.Conclusion != nil &&
@@ -206,6 +316,113 @@ func (p *githubPlatform) CheckAllStatusSucceeded(owner, repository,
 	return succeeded, err
 }
 
+// ListFailedStatuses returns the provided statuses which have a failed check
+// run, check runs which do not come from the trusted source of a status are
+// ignored
+func (p *githubPlatform) ListFailedStatuses(owner, repository, commitSha string,
+	statuses []string, sources map[string]*StatusSource) (failed []string, err error) {
+	if len(statuses) == 0 {
+		return
+	}
+
+	opts := &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{
+			Page:    0,
+			PerPage: githubPerPage,
+		},
+	}
+
+	for {
+		getCheckRun, resp, err := p.client.Checks.ListCheckRunsForRef(p.context,
+			owner, repository, commitSha, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, check := range getCheckRun.CheckRuns {
+			checkStatus := &Status{
+				Status: check.GetStatus(),
+				State:  check.GetConclusion(),
+			}
+			for _, status := range statuses {
+				if check.GetName() == status &&
+					isGithubCheckRunFromSource(check, sources[status]) &&
+					checkStatus.IsFailed() {
+					failed = appendUnique(failed, status)
+				}
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	return failed, nil
+}
+
+// ListRequiredStatuses returns the required status checks of the branch
+// protection rule applied to the release target branch. If the release target
+// is a commit, the default branch of the repository is used instead. Returns
+// an empty list if the branch is not protected
+func (p *githubPlatform) ListRequiredStatuses(owner, repository string,
+	release *Release) (statuses []string, err error) {
+	branch := release.CommitSha
+	if branch == "" || commitShaRegexp.MatchString(branch) {
+		repo, _, err := p.client.Repositories.Get(p.context, owner, repository)
+		if err != nil {
+			return nil, err
+		}
+		branch = repo.GetDefaultBranch()
+	}
+
+	checks, resp, err := p.client.Repositories.GetRequiredStatusChecks(p.context,
+		owner, repository, branch)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, context := range checks.Contexts {
+		statuses = appendUnique(statuses, context)
+	}
+	for _, check := range checks.Checks {
+		statuses = appendUnique(statuses, check.Context)
+	}
+
+	return statuses, nil
+}
+
+// isGithubCheckRunFromSource returns true if the check run has been created by
+// the trusted source or if no source is defined
+func isGithubCheckRunFromSource(check *github.CheckRun, source *StatusSource) bool {
+	if source == nil {
+		return true
+	}
+
+	if source.Creator != "" || source.PipelineSource != "" {
+		return false
+	}
+
+	if source.App == "" {
+		return true
+	}
+
+	if check.App == nil {
+		return false
+	}
+
+	if check.App.ID != nil && strconv.FormatInt(*check.App.ID, 10) == source.App {
+		return true
+	}
+
+	return check.App.Slug != nil && *check.App.Slug == source.App
+}
+
 // CreateFile create a file with content at a given path
 // This function is only called by integration tests
 func (p *githubPlatform) CreateFile(owner, repository, path, branch, commitMessage, body string) (err error) {
@@ -295,6 +512,13 @@ func (p *githubPlatform) CreateStatus(owner, repository string, status *Status)
 	return
 }
 
+// DeleteRelease delete a release, the tag associated to the release is kept
+func (p *githubPlatform) DeleteRelease(owner, repository string, release *Release) (err error) {
+	_, err = p.client.Repositories.DeleteRelease(p.context, owner, repository,
+		release.ID.(int64))
+	return
+}
+
 // DeleteRepository delete a repository
 // This function is only called by integration tests
 func (p *githubPlatform) DeleteRepository(owner, repository string) (err error) {
@@ -302,6 +526,31 @@ func (p *githubPlatform) DeleteRepository(owner, repository string) (err error)
 	return
 }
 
+// GetCommit returns the metadata of a commit, the commit can be referenced by
+// its sha or by a branch name
+func (p *githubPlatform) GetCommit(owner, repository, commitSha string) (commit *Commit, err error) {
+	c, _, err := p.client.Repositories.GetCommit(p.context, owner, repository,
+		commitSha, nil)
+	if err != nil {
+		return
+	}
+
+	return &Commit{
+		Sha:         c.GetSHA(),
+		Message:     c.GetCommit().GetMessage(),
+		Author:      c.GetCommit().GetAuthor().GetName(),
+		AuthorEmail: c.GetCommit().GetAuthor().GetEmail(),
+		Date:        c.GetCommit().GetAuthor().GetDate(),
+	}, nil
+}
+
+// ListTopics returns the topics of a repository
+func (p *githubPlatform) ListTopics(owner, repository string) (topics []string, err error) {
+	topics, _, err = p.client.Repositories.ListAllTopics(p.context, owner,
+		repository)
+	return
+}
+
 // GetStatus from provided commit and status name
 func (p *githubPlatform) GetStatus(owner, repository, commitSha, statusName string) (status *Status, err error) {
 	statusList, err := p.ListStatuses(owner, repository, commitSha)
@@ -319,6 +568,145 @@ func (p *githubPlatform) GetStatus(owner, repository, commitSha, statusName stri
 	return
 }
 
+// GetSignature returns the signature verification of the release tag or
+// commit. Lightweight tags are reported as unsigned
+func (p *githubPlatform) GetSignature(owner, repository string, release *Release,
+	target string) (signature *Signature, err error) {
+	var verification *github.SignatureVerification
+
+	switch target {
+	case SignatureTargetCommit:
+		commit, _, err := p.client.Repositories.GetCommit(p.context, owner,
+			repository, release.CommitSha, nil)
+		if err != nil {
+			return nil, err
+		}
+		verification = commit.GetCommit().GetVerification()
+	case SignatureTargetTag:
+		ref, _, err := p.client.Git.GetRef(p.context, owner, repository,
+			"tags/"+release.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if ref.GetObject().GetType() != "tag" {
+			return &Signature{Reason: "unsigned"}, nil
+		}
+
+		tag, _, err := p.client.Git.GetTag(p.context, owner, repository,
+			ref.GetObject().GetSHA())
+		if err != nil {
+			return nil, err
+		}
+		verification = tag.Verification
+	default:
+		return nil, fmt.Errorf("signature target %s is not recognized", target)
+	}
+
+	if verification == nil {
+		return &Signature{Reason: "unsigned"}, nil
+	}
+
+	return &Signature{
+		Verified:  verification.GetVerified(),
+		Reason:    verification.GetReason(),
+		Payload:   verification.GetPayload(),
+		Signature: verification.GetSignature(),
+	}, nil
+}
+
+// RetryStatus re-run a check run. Failed jobs of Github Actions workflow runs
+// are re-run using the Actions API, other check runs are re-requested. If the
+// check run can't be re-requested individually, then the whole check suite is
+// re-requested
+func (p *githubPlatform) RetryStatus(owner, repository string, status *Status) (err error) {
+	checkRunID, ok := status.ID.(int64)
+	if !ok {
+		return fmt.Errorf("invalid check run ID %v", status.ID)
+	}
+
+	checkRun, _, err := p.client.Checks.GetCheckRun(p.context, owner, repository,
+		checkRunID)
+	if err != nil {
+		return
+	}
+
+	if checkRun.GetApp().GetSlug() == githubActionsAppSlug {
+		return p.rerunFailedJobs(owner, repository, checkRunID)
+	}
+
+	req, err := p.client.NewRequest("POST", fmt.Sprintf(
+		"repos/%s/%s/check-runs/%d/rerequest", owner, repository, checkRunID), nil)
+	if err != nil {
+		return
+	}
+
+	_, rerequestErr := p.client.Do(p.context, req, nil)
+	if rerequestErr == nil {
+		return nil
+	}
+
+	checkSuiteID := checkRun.GetCheckSuite().GetID()
+
+	log.Debug().
+		Err(rerequestErr).
+		Str("owner", owner).
+		Str("repository", repository).
+		Msgf("Couldn't re-request check run %d, re-requesting check suite %d",
+			checkRunID, checkSuiteID)
+
+	if _, err = p.client.Checks.ReRequestCheckSuite(p.context, owner, repository,
+		checkSuiteID); err != nil {
+		return fmt.Errorf("couldn't re-request check run %d: %w, re-requesting check suite %d also failed: %w",
+			checkRunID, rerequestErr, checkSuiteID, err)
+	}
+	return nil
+}
+
+// rerunFailedJobs re-run the failed jobs of the Github Actions workflow run
+// the job belongs to, the ID of a check run created by Github Actions is the
+// ID of the job
+func (p *githubPlatform) rerunFailedJobs(owner, repository string, jobID int64) (err error) {
+	job, _, err := p.client.Actions.GetWorkflowJobByID(p.context, owner, repository,
+		jobID)
+	if err != nil {
+		return
+	}
+
+	req, err := p.client.NewRequest("POST", fmt.Sprintf(
+		"repos/%s/%s/actions/runs/%d/rerun-failed-jobs", owner, repository,
+		job.GetRunID()), nil)
+	if err != nil {
+		return
+	}
+
+	_, err = p.client.Do(p.context, req, nil)
+	return
+}
+
+// TriggerWorkflow dispatch a workflow_dispatch event to a Github Actions
+// workflow. Draft releases tags do not exist until the release is published,
+// therefore the release target commitish is used as default ref
+func (p *githubPlatform) TriggerWorkflow(owner, repository string, release *Release,
+	workflow *Workflow) (err error) {
+	ref := workflow.Ref
+	if ref == "" {
+		ref = release.CommitSha
+	}
+
+	inputs := make(map[string]interface{}, len(workflow.Inputs))
+	for key, value := range workflow.Inputs {
+		inputs[key] = value
+	}
+
+	_, err = p.client.Actions.CreateWorkflowDispatchEventByFileName(p.context,
+		owner, repository, workflow.Name, github.CreateWorkflowDispatchEventRequest{
+			Ref:    ref,
+			Inputs: inputs,
+		})
+	return
+}
+
 // ListIssuesByAuthor from a given repository
 func (p *githubPlatform) ListIssuesByAuthor(owner, repository string,
 	author interface{}) (issueList []*Issue, err error) {
@@ -355,6 +743,96 @@ func (p *githubPlatform) ListIssuesByAuthor(owner, repository string,
 	return issueList, err
 }
 
+// SearchIssues returns the open issues matching the query. Github issues API
+// also returns pull requests, they are filtered out unless requested
+func (p *githubPlatform) SearchIssues(owner, repository string,
+	query *IssueQuery) (issueList []*Issue, err error) {
+	opts := &github.IssueListByRepoOptions{
+		State: "open",
+		ListOptions: github.ListOptions{
+			Page:    0,
+			PerPage: githubPerPage,
+		},
+	}
+
+	if query.Label != "" {
+		opts.Labels = []string{query.Label}
+	}
+
+	if query.Milestone != "" {
+		number, err := p.findMilestone(owner, repository, query.Milestone)
+		if err != nil {
+			return nil, err
+		}
+		if number == 0 {
+			return nil, nil
+		}
+		opts.Milestone = strconv.Itoa(number)
+	}
+
+	for {
+		issuesFromRepo, resp, err := p.client.Issues.ListByRepo(p.context, owner,
+			repository, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issuesFromRepo {
+			if issue.IsPullRequest() && !query.PullRequests {
+				continue
+			}
+			issueList = append(issueList, &Issue{
+				Body:  issue.GetBody(),
+				ID:    issue.GetNumber(),
+				Title: issue.GetTitle(),
+				URL:   issue.GetHTMLURL(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	return issueList, err
+}
+
+// findMilestone returns the number of the milestone matching the title, 0 if
+// not found
+func (p *githubPlatform) findMilestone(owner, repository, title string) (int, error) {
+	opts := &github.MilestoneListOptions{
+		State: "all",
+		ListOptions: github.ListOptions{
+			Page:    0,
+			PerPage: githubPerPage,
+		},
+	}
+
+	for {
+		milestones, resp, err := p.client.Issues.ListMilestones(p.context, owner,
+			repository, opts)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, milestone := range milestones {
+			if milestone.GetTitle() == title {
+				return milestone.GetNumber(), nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	return 0, nil
+}
+
 // ListStatuses attached to a given commit sha
 func (p *githubPlatform) ListStatuses(owner, repository, commitSha string) (statusList []*Status, err error) {
 	opts := &github.ListCheckRunsOptions{
@@ -374,6 +852,7 @@ func (p *githubPlatform) ListStatuses(owner, repository, commitSha string) (stat
 		for _, checkRun := range getCheckRun.CheckRuns {
 			cr := &Status{
 				CommitSha: *checkRun.HeadSHA,
+				ID:        checkRun.GetID(),
 				Name:      *checkRun.Name,
 				Status:    *checkRun.Status,
 			}
@@ -4,7 +4,10 @@ package platforms
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v43/github"
 	"github.com/kylelemons/godebug/pretty"
@@ -126,6 +129,7 @@ func TestGithubCheckAllStatusSucceeded(t *testing.T) {
 		name      string
 		checkRuns []*github.CheckRun
 		statuses  []string
+		sources   map[string]*StatusSource
 		expected  bool
 	}{
 		{
@@ -194,6 +198,54 @@ func TestGithubCheckAllStatusSucceeded(t *testing.T) {
 			statuses: []string{"happy flow", "feature A", "feature B"},
 			expected: false,
 		},
+		{
+			name: "should return true if check runs come from the trusted app",
+			checkRuns: []*github.CheckRun{
+				{
+					Name:       github.String("happy flow"),
+					Status:     github.String("completed"),
+					Conclusion: github.String("success"),
+					App: &github.App{
+						ID:   github.Int64(1234),
+						Slug: github.String("e2e-runner"),
+					},
+				},
+				{
+					Name:       github.String("feature B"),
+					Status:     github.String("completed"),
+					Conclusion: github.String("success"),
+					App: &github.App{
+						ID:   github.Int64(5678),
+						Slug: github.String("other-app"),
+					},
+				},
+			},
+			statuses: []string{"happy flow", "feature B"},
+			sources: map[string]*StatusSource{
+				"happy flow": {App: "e2e-runner"},
+				"feature B":  {App: "5678"},
+			},
+			expected: true,
+		},
+		{
+			name: "should return false if a check run doesn't come from the trusted app",
+			checkRuns: []*github.CheckRun{
+				{
+					Name:       github.String("happy flow"),
+					Status:     github.String("completed"),
+					Conclusion: github.String("success"),
+					App: &github.App{
+						ID:   github.Int64(5678),
+						Slug: github.String("other-app"),
+					},
+				},
+			},
+			statuses: []string{"happy flow"},
+			sources: map[string]*StatusSource{
+				"happy flow": {App: "e2e-runner"},
+			},
+			expected: false,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -210,7 +262,8 @@ func TestGithubCheckAllStatusSucceeded(t *testing.T) {
 				context: context.Background(),
 			}
 
-			result, err := gh.CheckAllStatusSucceeded("a", "a", "a", testCase.statuses)
+			result, err := gh.CheckAllStatusSucceeded("a", "a", "a", testCase.statuses,
+				testCase.sources)
 			if err != nil {
 				t.Errorf("Error checking status check: %#v", err)
 			}
@@ -221,17 +274,73 @@ func TestGithubCheckAllStatusSucceeded(t *testing.T) {
 	}
 }
 
+func TestGithubListFailedStatuses(t *testing.T) {
+	checkRuns := []*github.CheckRun{
+		{
+			Name:       github.String("happy flow"),
+			Status:     github.String("completed"),
+			Conclusion: github.String("failure"),
+			App:        &github.App{Slug: github.String("spoofer")},
+		},
+		{
+			Name:       github.String("happy flow"),
+			Status:     github.String("completed"),
+			Conclusion: github.String("success"),
+			App:        &github.App{Slug: github.String("e2e-runner")},
+		},
+		{
+			Name:       github.String("feature B"),
+			Status:     github.String("completed"),
+			Conclusion: github.String("failure"),
+		},
+		{
+			Name:       github.String("feature C"),
+			Status:     github.String("completed"),
+			Conclusion: github.String("skipped"),
+		},
+	}
+
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposCommitsCheckRunsByOwnerByRepoByRef,
+			github.ListCheckRunsResults{CheckRuns: checkRuns},
+		),
+	)
+
+	gh := githubPlatform{
+		client:  github.NewClient(mockedHTTPClient),
+		context: context.Background(),
+	}
+
+	t.Run("should ignore failed check runs which do not come from the trusted source",
+		func(t *testing.T) {
+			result, err := gh.ListFailedStatuses("a", "a", "a",
+				[]string{"happy flow", "feature B", "feature C"},
+				map[string]*StatusSource{
+					"happy flow": {App: "e2e-runner"},
+				})
+			if err != nil {
+				t.Errorf("Error listing failed statuses: %#v", err)
+			}
+			if diff := pretty.Compare(result, []string{"feature B"}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
+
 func TestGithubListStatuses(t *testing.T) {
 	t.Run("should list statuses", func(t *testing.T) {
 		expected := []*Status{
 			{
 				CommitSha: "abcd1234",
+				ID:        int64(1),
 				Name:      "happy flow",
 				Status:    "completed",
 				State:     "success",
 			},
 			{
 				CommitSha: "abcd1234",
+				ID:        int64(2),
 				Name:      "feature A",
 				Status:    "queued",
 				State:     "",
@@ -244,12 +353,14 @@ func TestGithubListStatuses(t *testing.T) {
 				github.ListCheckRunsResults{CheckRuns: []*github.CheckRun{
 					{
 						HeadSHA:    github.String("abcd1234"),
+						ID:         github.Int64(1),
 						Name:       github.String("happy flow"),
 						Status:     github.String("completed"),
 						Conclusion: github.String("success"),
 					},
 					{
 						HeadSHA: github.String("abcd1234"),
+						ID:      github.Int64(2),
 						Name:    github.String("feature A"),
 						Status:  github.String("queued"),
 					},
@@ -272,3 +383,282 @@ func TestGithubListStatuses(t *testing.T) {
 		}
 	})
 }
+
+func TestGithubListRequiredStatuses(t *testing.T) {
+	t.Run("should list required status checks of the default branch",
+		func(t *testing.T) {
+			mockedHTTPClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposByOwnerByRepo,
+					github.Repository{
+						DefaultBranch: github.String("main"),
+					},
+				),
+				mock.WithRequestMatch(
+					mock.GetReposBranchesProtectionRequiredStatusChecksByOwnerByRepoByBranch,
+					github.RequiredStatusChecks{
+						Contexts: []string{"e2e-happyflow"},
+						Checks: []*github.RequiredStatusCheck{
+							{Context: "e2e-happyflow"},
+							{Context: "e2e-useraccountflow"},
+						},
+					},
+				),
+			)
+
+			gh := &githubPlatform{
+				client:  github.NewClient(mockedHTTPClient),
+				context: context.Background(),
+			}
+
+			result, err := gh.ListRequiredStatuses("a", "a", &Release{
+				CommitSha: "5d7e1b7d4a1f2c3e4b5a69788796a5b4c3d2e1f0",
+			})
+			if err != nil {
+				t.Errorf("Error listing required statuses: %#v", err)
+			}
+
+			expected := []string{"e2e-happyflow", "e2e-useraccountflow"}
+			if diff := pretty.Compare(result, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
+
+func TestGithubSearchIssues(t *testing.T) {
+	t.Run("should list open issues of the milestone without pull requests",
+		func(t *testing.T) {
+			mockedHTTPClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposMilestonesByOwnerByRepo,
+					[]*github.Milestone{
+						{Number: github.Int(1), Title: github.String("v1.2.2")},
+						{Number: github.Int(2), Title: github.String("v1.2.3")},
+					},
+				),
+				mock.WithRequestMatch(
+					mock.GetReposIssuesByOwnerByRepo,
+					[]*github.Issue{
+						{
+							Number:  github.Int(12),
+							Title:   github.String("Login is broken"),
+							HTMLURL: github.String("https://github.com/a/a/issues/12"),
+						},
+						{
+							Number:           github.Int(13),
+							Title:            github.String("Fix login"),
+							HTMLURL:          github.String("https://github.com/a/a/pull/13"),
+							PullRequestLinks: &github.PullRequestLinks{},
+						},
+					},
+				),
+			)
+
+			gh := &githubPlatform{
+				client:  github.NewClient(mockedHTTPClient),
+				context: context.Background(),
+			}
+
+			result, err := gh.SearchIssues("a", "a", &IssueQuery{Milestone: "v1.2.3"})
+			if err != nil {
+				t.Errorf("Error searching issues: %#v", err)
+			}
+
+			expected := []*Issue{
+				{
+					ID:    12,
+					Title: "Login is broken",
+					URL:   "https://github.com/a/a/issues/12",
+				},
+			}
+			if diff := pretty.Compare(result, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
+
+func TestGithubListTopics(t *testing.T) {
+	t.Run("should return the repository topics", func(t *testing.T) {
+		mockedHTTPClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetReposTopicsByOwnerByRepo,
+				map[string][]string{
+					"names": {"release-gated", "backend"},
+				},
+			),
+		)
+
+		gh := &githubPlatform{
+			client:  github.NewClient(mockedHTTPClient),
+			context: context.Background(),
+		}
+
+		result, err := gh.ListTopics("a", "a")
+		if err != nil {
+			t.Errorf("Error listing topics: %#v", err)
+		}
+
+		expected := []string{"release-gated", "backend"}
+		if diff := pretty.Compare(result, expected); diff != "" {
+			t.Errorf("diff: (-got +want)\n%s", diff)
+		}
+	})
+}
+
+func TestGithubGetCommit(t *testing.T) {
+	t.Run("should return the commit metadata", func(t *testing.T) {
+		date := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		mockedHTTPClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetReposCommitsByOwnerByRepoByRef,
+				github.RepositoryCommit{
+					SHA: github.String("abcd1234"),
+					Commit: &github.Commit{
+						Message: github.String("feat: add policy"),
+						Author: &github.CommitAuthor{
+							Name:  github.String("Jane"),
+							Email: github.String("jane@example.com"),
+							Date:  &date,
+						},
+					},
+				},
+			),
+		)
+
+		gh := &githubPlatform{
+			client:  github.NewClient(mockedHTTPClient),
+			context: context.Background(),
+		}
+
+		result, err := gh.GetCommit("a", "a", "abcd1234")
+		if err != nil {
+			t.Errorf("Error getting commit: %#v", err)
+		}
+
+		expected := &Commit{
+			Sha:         "abcd1234",
+			Message:     "feat: add policy",
+			Author:      "Jane",
+			AuthorEmail: "jane@example.com",
+			Date:        date,
+		}
+		if diff := pretty.Compare(result, expected); diff != "" {
+			t.Errorf("diff: (-got +want)\n%s", diff)
+		}
+	})
+}
+
+func TestGithubRetryStatus(t *testing.T) {
+	rerequestCheckRun := mock.EndpointPattern{
+		Pattern: "/repos/{owner}/{repo}/check-runs/{check_run_id}/rerequest",
+		Method:  "POST",
+	}
+	rerunFailedJobs := mock.EndpointPattern{
+		Pattern: "/repos/{owner}/{repo}/actions/runs/{run_id}/rerun-failed-jobs",
+		Method:  "POST",
+	}
+
+	t.Run("should re-run the failed jobs of Github Actions workflow runs",
+		func(t *testing.T) {
+			mockedHTTPClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposCheckRunsByOwnerByRepoByCheckRunId,
+					github.CheckRun{
+						ID:  github.Int64(12),
+						App: &github.App{Slug: github.String("github-actions")},
+					},
+				),
+				mock.WithRequestMatch(
+					mock.GetReposActionsJobsByOwnerByRepoByJobId,
+					github.WorkflowJob{ID: github.Int64(12), RunID: github.Int64(34)},
+				),
+				mock.WithRequestMatchHandler(
+					rerunFailedJobs,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if r.URL.Path != "/repos/a/a/actions/runs/34/rerun-failed-jobs" {
+							t.Errorf("Unexpected path %s", r.URL.Path)
+						}
+						w.WriteHeader(http.StatusCreated)
+					}),
+				),
+			)
+
+			gh := &githubPlatform{
+				client:  github.NewClient(mockedHTTPClient),
+				context: context.Background(),
+			}
+
+			if err := gh.RetryStatus("a", "a", &Status{ID: int64(12)}); err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+		})
+
+	t.Run("should return the check run error if the check suite can't be re-requested",
+		func(t *testing.T) {
+			mockedHTTPClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposCheckRunsByOwnerByRepoByCheckRunId,
+					github.CheckRun{
+						ID:         github.Int64(12),
+						App:        &github.App{Slug: github.String("ci")},
+						CheckSuite: &github.CheckSuite{ID: github.Int64(56)},
+					},
+				),
+				mock.WithRequestMatchHandler(
+					rerequestCheckRun,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusForbidden)
+						_, _ = w.Write([]byte(`{"message":"check run can't be re-requested"}`))
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposCheckSuitesRerequestByOwnerByRepoByCheckSuiteId,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusForbidden)
+						_, _ = w.Write([]byte(`{"message":"check suite can't be re-requested"}`))
+					}),
+				),
+			)
+
+			gh := &githubPlatform{
+				client:  github.NewClient(mockedHTTPClient),
+				context: context.Background(),
+			}
+
+			err := gh.RetryStatus("a", "a", &Status{ID: int64(12)})
+
+			var errorResponse *github.ErrorResponse
+			if !errors.As(err, &errorResponse) {
+				t.Fatalf("Expected a Github error, got %#v", err)
+			}
+			if errorResponse.Message != "check run can't be re-requested" {
+				t.Errorf("Expected the check run error to be returned, got %s",
+					errorResponse.Message)
+			}
+		})
+}
+
+func TestGithubReadFile(t *testing.T) {
+	t.Run("should return a file not found error if the file doesn't exist",
+		func(t *testing.T) {
+			mockedHTTPClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposContentsByOwnerByRepoByPath,
+					[]*github.RepositoryContent{
+						{Name: github.String("README.md")},
+					},
+				),
+			)
+
+			gh := &githubPlatform{
+				client:  github.NewClient(mockedHTTPClient),
+				context: context.Background(),
+			}
+
+			_, err := gh.ReadFile("a", "a", ".grgate.yaml", "")
+			if !errors.Is(err, ErrFileNotFound) {
+				t.Errorf("Expected file not found error, got %#v", err)
+			}
+		})
+}
@@ -2,8 +2,11 @@ package platforms
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"sort"
 	"time"
 
 	"github.com/xanzy/go-gitlab"
@@ -45,11 +48,20 @@ func NewGitlab(config *GitlabConfig) (platform Platform, err error) {
 	return
 }
 
-// ReadFile retrieve file located at the provided path in a given Gitlab repository
-func (p *gitlabPlatform) ReadFile(owner, repository, path string) (content io.Reader, err error) {
-	r, _, err := p.client.RepositoryFiles.GetRawFile(getPID(owner, repository),
-		path, nil)
+// ReadFile retrieve file located at the provided path in a given Gitlab
+// repository, the file is read from the default branch if ref is empty.
+// ErrFileNotFound is returned if the file doesn't exist
+func (p *gitlabPlatform) ReadFile(owner, repository, path, ref string) (content io.Reader, err error) {
+	var opts *gitlab.GetRawFileOptions
+	if ref != "" {
+		opts = &gitlab.GetRawFileOptions{Ref: gitlab.String(ref)}
+	}
+	r, resp, err := p.client.RepositoryFiles.GetRawFile(getPID(owner, repository),
+		path, opts)
 	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, err)
+		}
 		return
 	}
 	content = bytes.NewBuffer(r)
@@ -76,6 +88,16 @@ func (p *gitlabPlatform) ListReleases(owner, repository string) (releases []*Rel
 			// if the release is in the future, then this is a "draft release"
 			draft := release.ReleasedAt.After(time.Now().UTC())
 
+			var publishedAt time.Time
+			if !draft {
+				publishedAt = *release.ReleasedAt
+			}
+
+			var createdAt time.Time
+			if release.CreatedAt != nil {
+				createdAt = *release.CreatedAt
+			}
+
 			releases = append(releases, &Release{
 				CommitSha:   release.Commit.ID,
 				ID:          release.TagName,
@@ -84,6 +106,8 @@ func (p *gitlabPlatform) ListReleases(owner, repository string) (releases []*Rel
 				ReleaseNote: release.Description,
 				Tag:         release.TagName,
 				Draft:       draft,
+				PublishedAt: publishedAt,
+				CreatedAt:   createdAt,
 			})
 		}
 
@@ -154,13 +178,107 @@ func (p *gitlabPlatform) PublishRelease(owner, repository string, release *Relea
 	return
 }
 
-// CheckAllStatusSucceeded checks that all the provided statuses succeeded
+// UnpublishRelease revert a published release to draft by moving its release
+// date in the future and update its release note
+func (p *gitlabPlatform) UnpublishRelease(owner, repository string, release *Release) (unpublished bool, err error) {
+	releasedAt := time.Now().UTC().Add(futureReleaseTime)
+
+	opts := &gitlab.UpdateReleaseOptions{
+		ReleasedAt:  &releasedAt,
+		Description: &release.ReleaseNote,
+		Name:        &release.Name,
+	}
+
+	_, _, err = p.client.Releases.UpdateRelease(getPID(owner, repository),
+		release.ID.(string), opts, nil)
+	if err != nil {
+		return
+	}
+
+	unpublished = true
+	return
+}
+
+// ListReleaseAssets returns the links attached to a release
+func (p *gitlabPlatform) ListReleaseAssets(owner, repository string, release *Release) (assets []*ReleaseAsset, err error) {
+	opts := &gitlab.ListReleaseLinksOptions{
+		Page:    0,
+		PerPage: gitlabPerPage,
+	}
+
+	for {
+		links, resp, err := p.client.ReleaseLinks.ListReleaseLinks(getPID(owner,
+			repository), release.Tag, opts, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, link := range links {
+			url := link.DirectAssetURL
+			if url == "" {
+				url = link.URL
+			}
+
+			assets = append(assets, &ReleaseAsset{
+				ID:   link.ID,
+				Name: link.Name,
+				URL:  url,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return assets, err
+}
+
+// DownloadReleaseAsset returns the content of a release link, the caller is
+// responsible for closing it. The Gitlab token is only sent if the link is
+// hosted on the Gitlab instance
+func (p *gitlabPlatform) DownloadReleaseAsset(_, _ string, asset *ReleaseAsset) (content io.ReadCloser, err error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		asset.URL, http.NoBody)
+	if err != nil {
+		return
+	}
+
+	if req.URL.Host == p.client.BaseURL().Host {
+		req.Header.Set("PRIVATE-TOKEN", p.config.Token)
+	}
+
+	resp, err := assetClient.Do(req)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("couldn't download asset %s, got status %d",
+			asset.Name, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// CheckAllStatusSucceeded checks that all the provided statuses succeeded,
+// commit statuses which do not come from the trusted source of a status are
+// ignored
 func (p *gitlabPlatform) CheckAllStatusSucceeded(owner, repository,
-	commitSha string, statuses []string) (succeeded bool, err error) {
+	commitSha string, statuses []string, sources map[string]*StatusSource) (succeeded bool, err error) {
 	if len(statuses) == 0 {
 		return true, nil
 	}
 
+	pipelineSources, err := p.listJobPipelineSources(owner, repository,
+		commitSha, sources)
+	if err != nil {
+		return false, err
+	}
+
 	opts := &gitlab.GetCommitStatusesOptions{
 		ListOptions: gitlab.ListOptions{
 			Page:    0,
@@ -179,7 +297,9 @@ func (p *gitlabPlatform) CheckAllStatusSucceeded(owner, repository,
 		// for all statuses, check if the provided one are all successful
 		for _, commitStatus := range commitStatuses {
 			for _, status := range statuses {
-				if commitStatus.Name == status && commitStatus.Status == successStatusValue {
+				if commitStatus.Name == status &&
+					isGitlabCommitStatusFromSource(commitStatus, pipelineSources, sources[status]) &&
+					commitStatus.Status == successStatusValue {
 					succeededStatus++
 				}
 			}
@@ -197,6 +317,238 @@ func (p *gitlabPlatform) CheckAllStatusSucceeded(owner, repository,
 	return succeeded, err
 }
 
+// ListFailedStatuses returns the provided statuses which have a failed commit
+// status, commit statuses which do not come from the trusted source of a
+// status are ignored
+func (p *gitlabPlatform) ListFailedStatuses(owner, repository, commitSha string,
+	statuses []string, sources map[string]*StatusSource) (failed []string, err error) {
+	if len(statuses) == 0 {
+		return
+	}
+
+	pipelineSources, err := p.listJobPipelineSources(owner, repository,
+		commitSha, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &gitlab.GetCommitStatusesOptions{
+		ListOptions: gitlab.ListOptions{
+			Page:    0,
+			PerPage: gitlabPerPage,
+		},
+	}
+
+	for {
+		commitStatuses, resp, err := p.client.Commits.GetCommitStatuses(getPID(
+			owner, repository), commitSha, opts, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, commitStatus := range commitStatuses {
+			for _, status := range statuses {
+				if commitStatus.Name == status &&
+					isGitlabCommitStatusFromSource(commitStatus, pipelineSources, sources[status]) &&
+					(&Status{Status: commitStatus.Status}).IsFailed() {
+					failed = appendUnique(failed, status)
+				}
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	return failed, nil
+}
+
+// listCommitPipelines returns all the pipelines which ran against a commit
+func (p *gitlabPlatform) listCommitPipelines(owner, repository,
+	commitSha string) (pipelines []*gitlab.PipelineInfo, err error) {
+	opts := &gitlab.ListProjectPipelinesOptions{
+		SHA: gitlab.String(commitSha),
+		ListOptions: gitlab.ListOptions{
+			Page:    0,
+			PerPage: gitlabPerPage,
+		},
+	}
+
+	for {
+		pipelineList, resp, err := p.client.Pipelines.ListProjectPipelines(
+			getPID(owner, repository), opts, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		pipelines = append(pipelines, pipelineList...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	return
+}
+
+// listJobPipelineSources returns the source of the pipeline each job of a
+// commit belong to, indexed by job ID. The Gitlab API is only called if one of
+// the provided status sources require a pipeline source
+func (p *gitlabPlatform) listJobPipelineSources(owner, repository, commitSha string,
+	sources map[string]*StatusSource) (pipelineSources map[int]string, err error) {
+	pipelineSources = make(map[int]string)
+
+	required := false
+	for _, source := range sources {
+		if source != nil && source.PipelineSource != "" {
+			required = true
+			break
+		}
+	}
+	if !required {
+		return
+	}
+
+	pipelines, err := p.listCommitPipelines(owner, repository, commitSha)
+	if err != nil {
+		return
+	}
+
+	for _, pipeline := range pipelines {
+		opts := &gitlab.ListJobsOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    0,
+				PerPage: gitlabPerPage,
+			},
+		}
+
+		for {
+			jobs, resp, err := p.client.Jobs.ListPipelineJobs(getPID(owner,
+				repository), pipeline.ID, opts, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, job := range jobs {
+				pipelineSources[job.ID] = pipeline.Source
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+
+			opts.ListOptions.Page = resp.NextPage
+		}
+	}
+
+	return
+}
+
+// ListRequiredStatuses returns the external status checks defined in the
+// project and the name of the jobs of the pipelines which ran against the
+// release commit
+func (p *gitlabPlatform) ListRequiredStatuses(owner, repository string,
+	release *Release) (statuses []string, err error) {
+	opts := &gitlab.ListOptions{
+		Page:    0,
+		PerPage: gitlabPerPage,
+	}
+
+	for {
+		checks, resp, err := p.client.ExternalStatusChecks.ListProjectStatusChecks(
+			getPID(owner, repository), opts, nil)
+		if err != nil {
+			// external status checks are only available in Gitlab Ultimate
+			if resp != nil && (resp.StatusCode == http.StatusNotFound ||
+				resp.StatusCode == http.StatusForbidden) {
+				break
+			}
+			return nil, err
+		}
+
+		for _, check := range checks {
+			statuses = appendUnique(statuses, check.Name)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	pipelines, err := p.listCommitPipelines(owner, repository, release.CommitSha)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pipeline := range pipelines {
+		jobOpts := &gitlab.ListJobsOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    0,
+				PerPage: gitlabPerPage,
+			},
+		}
+
+		for {
+			jobs, resp, err := p.client.Jobs.ListPipelineJobs(getPID(owner,
+				repository), pipeline.ID, jobOpts, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, job := range jobs {
+				// jobs allowed to fail are not required
+				if job.AllowFailure {
+					continue
+				}
+				statuses = appendUnique(statuses, job.Name)
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+
+			jobOpts.ListOptions.Page = resp.NextPage
+		}
+	}
+
+	return statuses, nil
+}
+
+// isGitlabCommitStatusFromSource returns true if the commit status has been
+// created by the trusted source or if no source is defined
+func isGitlabCommitStatusFromSource(commitStatus *gitlab.CommitStatus,
+	pipelineSources map[int]string, source *StatusSource) bool {
+	if source == nil {
+		return true
+	}
+
+	if source.App != "" {
+		return false
+	}
+
+	if source.Creator != "" && commitStatus.Author.Username != source.Creator {
+		return false
+	}
+
+	if source.PipelineSource != "" {
+		// commit statuses set via the API belong to an "external" pipeline
+		pipelineSource, ok := pipelineSources[commitStatus.ID]
+		if !ok {
+			pipelineSource = "external"
+		}
+		return pipelineSource == source.PipelineSource
+	}
+
+	return true
+}
+
 // CreateFile create a file with content at a given path
 // This function is only called by integration tests
 func (p *gitlabPlatform) CreateFile(owner, repository, path, branch, commitMessage, body string) (err error) {
@@ -286,6 +638,13 @@ func (p *gitlabPlatform) CreateStatus(owner, repository string, status *Status)
 	return
 }
 
+// DeleteRelease delete a release, the tag associated to the release is kept
+func (p *gitlabPlatform) DeleteRelease(owner, repository string, release *Release) (err error) {
+	_, _, err = p.client.Releases.DeleteRelease(getPID(owner, repository),
+		release.Tag)
+	return
+}
+
 // DeleteRepository delete a repository
 // This function is only called by integration tests
 func (p *gitlabPlatform) DeleteRepository(owner, repository string) (err error) {
@@ -293,6 +652,62 @@ func (p *gitlabPlatform) DeleteRepository(owner, repository string) (err error)
 	return
 }
 
+// GetCommit returns the metadata of a commit
+func (p *gitlabPlatform) GetCommit(owner, repository, commitSha string) (commit *Commit, err error) {
+	c, _, err := p.client.Commits.GetCommit(getPID(owner, repository), commitSha)
+	if err != nil {
+		return
+	}
+
+	commit = &Commit{
+		Sha:         c.ID,
+		Message:     c.Message,
+		Author:      c.AuthorName,
+		AuthorEmail: c.AuthorEmail,
+	}
+	if c.AuthoredDate != nil {
+		commit.Date = *c.AuthoredDate
+	}
+
+	return commit, nil
+}
+
+// ListTopics returns the topics of a project
+func (p *gitlabPlatform) ListTopics(owner, repository string) (topics []string, err error) {
+	project, _, err := p.client.Projects.GetProject(getPID(owner, repository), nil)
+	if err != nil {
+		return
+	}
+
+	return project.Topics, nil
+}
+
+// GetSignature returns the GPG signature verification of the release commit.
+// The Gitlab API doesn't expose tag signatures, an error is returned if the
+// target isn't the commit
+func (p *gitlabPlatform) GetSignature(owner, repository string, release *Release,
+	target string) (signature *Signature, err error) {
+	if target != SignatureTargetCommit {
+		return nil, fmt.Errorf("signature target %s is not supported on gitlab", target)
+	}
+
+	sig, resp, err := p.client.Commits.GetGPGSiganature(getPID(owner, repository),
+		release.CommitSha)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return &Signature{Reason: "unsigned"}, nil
+		}
+		return nil, err
+	}
+
+	return &Signature{
+		Verified: sig.VerificationStatus == "verified",
+		Reason:   sig.VerificationStatus,
+		KeyID:    sig.KeyPrimaryKeyID,
+		Signer:   sig.KeyUserEmail,
+	}, nil
+}
+
 // ListIssuesByAuthor from a given repository
 func (p *gitlabPlatform) ListIssuesByAuthor(owner, repository string,
 	author interface{}) (issueList []*Issue, err error) {
@@ -356,6 +771,7 @@ func (p *gitlabPlatform) ListStatuses(owner, repository, commitSha string) (stat
 	for _, commitStatus := range commitStatuses {
 		cr := &Status{
 			CommitSha: commitStatus.SHA,
+			ID:        commitStatus.ID,
 			Name:      commitStatus.Name,
 			Status:    commitStatus.Status,
 		}
@@ -366,6 +782,136 @@ func (p *gitlabPlatform) ListStatuses(owner, repository, commitSha string) (stat
 	return statusList, err
 }
 
+// RetryStatus retry the job associated to a commit status, statuses created
+// via the API are not associated to a job and can't be retried
+func (p *gitlabPlatform) RetryStatus(owner, repository string, status *Status) (err error) {
+	jobID, ok := status.ID.(int)
+	if !ok {
+		return fmt.Errorf("invalid job ID %v", status.ID)
+	}
+
+	_, _, err = p.client.Jobs.RetryJob(getPID(owner, repository), jobID)
+	return
+}
+
+// TriggerWorkflow create a pipeline against the release tag, inputs are passed
+// as pipeline variables
+func (p *gitlabPlatform) TriggerWorkflow(owner, repository string, release *Release,
+	workflow *Workflow) (err error) {
+	ref := workflow.Ref
+	if ref == "" {
+		ref = release.Tag
+	}
+
+	keys := make([]string, 0, len(workflow.Inputs))
+	for key := range workflow.Inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	variables := make([]*gitlab.PipelineVariableOptions, 0, len(keys))
+	for _, key := range keys {
+		variables = append(variables, &gitlab.PipelineVariableOptions{
+			Key:   gitlab.String(key),
+			Value: gitlab.String(workflow.Inputs[key]),
+		})
+	}
+
+	_, _, err = p.client.Pipelines.CreatePipeline(getPID(owner, repository),
+		&gitlab.CreatePipelineOptions{
+			Ref:       gitlab.String(ref),
+			Variables: &variables,
+		})
+	return
+}
+
+// SearchIssues returns the open issues, and merge requests if requested,
+// matching the query
+func (p *gitlabPlatform) SearchIssues(owner, repository string,
+	query *IssueQuery) (issueList []*Issue, err error) {
+	var labels *gitlab.Labels
+	if query.Label != "" {
+		labels = &gitlab.Labels{query.Label}
+	}
+
+	var milestone *string
+	if query.Milestone != "" {
+		milestone = gitlab.String(query.Milestone)
+	}
+
+	opts := &gitlab.ListProjectIssuesOptions{
+		State:     gitlab.String("opened"),
+		Labels:    labels,
+		Milestone: milestone,
+		ListOptions: gitlab.ListOptions{
+			Page:    0,
+			PerPage: gitlabPerPage,
+		},
+	}
+
+	for {
+		issues, resp, err := p.client.Issues.ListProjectIssues(getPID(owner,
+			repository), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			issueList = append(issueList, &Issue{
+				Body:  issue.Description,
+				ID:    issue.IID,
+				Title: issue.Title,
+				URL:   issue.WebURL,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	if !query.PullRequests {
+		return issueList, nil
+	}
+
+	mrOpts := &gitlab.ListProjectMergeRequestsOptions{
+		State:     gitlab.String("opened"),
+		Labels:    labels,
+		Milestone: milestone,
+		ListOptions: gitlab.ListOptions{
+			Page:    0,
+			PerPage: gitlabPerPage,
+		},
+	}
+
+	for {
+		mergeRequests, resp, err := p.client.MergeRequests.ListProjectMergeRequests(
+			getPID(owner, repository), mrOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mergeRequest := range mergeRequests {
+			issueList = append(issueList, &Issue{
+				Body:  mergeRequest.Description,
+				ID:    mergeRequest.IID,
+				Title: mergeRequest.Title,
+				URL:   mergeRequest.WebURL,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		mrOpts.ListOptions.Page = resp.NextPage
+	}
+
+	return issueList, nil
+}
+
 // UpdateIssue update an issue
 func (p *gitlabPlatform) UpdateIssue(owner, repository string, issue *Issue) (err error) {
 	opts := &gitlab.UpdateIssueOptions{
@@ -36,18 +36,18 @@ func (m *MockPlatform) EXPECT() *MockPlatformMockRecorder {
 }
 
 // CheckAllStatusSucceeded mocks base method.
-func (m *MockPlatform) CheckAllStatusSucceeded(arg0, arg1, arg2 string, arg3 []string) (bool, error) {
+func (m *MockPlatform) CheckAllStatusSucceeded(arg0, arg1, arg2 string, arg3 []string, arg4 map[string]*platforms.StatusSource) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckAllStatusSucceeded", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "CheckAllStatusSucceeded", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckAllStatusSucceeded indicates an expected call of CheckAllStatusSucceeded.
-func (mr *MockPlatformMockRecorder) CheckAllStatusSucceeded(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockPlatformMockRecorder) CheckAllStatusSucceeded(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAllStatusSucceeded", reflect.TypeOf((*MockPlatform)(nil).CheckAllStatusSucceeded), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAllStatusSucceeded", reflect.TypeOf((*MockPlatform)(nil).CheckAllStatusSucceeded), arg0, arg1, arg2, arg3, arg4)
 }
 
 // CreateFile mocks base method.
@@ -121,6 +121,20 @@ func (mr *MockPlatformMockRecorder) CreateStatus(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatus", reflect.TypeOf((*MockPlatform)(nil).CreateStatus), arg0, arg1, arg2)
 }
 
+// DeleteRelease mocks base method.
+func (m *MockPlatform) DeleteRelease(arg0, arg1 string, arg2 *platforms.Release) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRelease", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRelease indicates an expected call of DeleteRelease.
+func (mr *MockPlatformMockRecorder) DeleteRelease(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRelease", reflect.TypeOf((*MockPlatform)(nil).DeleteRelease), arg0, arg1, arg2)
+}
+
 // DeleteRepository mocks base method.
 func (m *MockPlatform) DeleteRepository(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -135,6 +149,51 @@ func (mr *MockPlatformMockRecorder) DeleteRepository(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepository", reflect.TypeOf((*MockPlatform)(nil).DeleteRepository), arg0, arg1)
 }
 
+// DownloadReleaseAsset mocks base method.
+func (m *MockPlatform) DownloadReleaseAsset(arg0, arg1 string, arg2 *platforms.ReleaseAsset) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadReleaseAsset", arg0, arg1, arg2)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadReleaseAsset indicates an expected call of DownloadReleaseAsset.
+func (mr *MockPlatformMockRecorder) DownloadReleaseAsset(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadReleaseAsset", reflect.TypeOf((*MockPlatform)(nil).DownloadReleaseAsset), arg0, arg1, arg2)
+}
+
+// GetCommit mocks base method.
+func (m *MockPlatform) GetCommit(arg0, arg1, arg2 string) (*platforms.Commit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*platforms.Commit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommit indicates an expected call of GetCommit.
+func (mr *MockPlatformMockRecorder) GetCommit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommit", reflect.TypeOf((*MockPlatform)(nil).GetCommit), arg0, arg1, arg2)
+}
+
+// GetSignature mocks base method.
+func (m *MockPlatform) GetSignature(arg0, arg1 string, arg2 *platforms.Release, arg3 string) (*platforms.Signature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSignature", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*platforms.Signature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSignature indicates an expected call of GetSignature.
+func (mr *MockPlatformMockRecorder) GetSignature(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSignature", reflect.TypeOf((*MockPlatform)(nil).GetSignature), arg0, arg1, arg2, arg3)
+}
+
 // GetStatus mocks base method.
 func (m *MockPlatform) GetStatus(arg0, arg1, arg2, arg3 string) (*platforms.Status, error) {
 	m.ctrl.T.Helper()
@@ -165,6 +224,21 @@ func (mr *MockPlatformMockRecorder) ListDraftReleases(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDraftReleases", reflect.TypeOf((*MockPlatform)(nil).ListDraftReleases), arg0, arg1)
 }
 
+// ListFailedStatuses mocks base method.
+func (m *MockPlatform) ListFailedStatuses(arg0, arg1, arg2 string, arg3 []string, arg4 map[string]*platforms.StatusSource) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailedStatuses", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailedStatuses indicates an expected call of ListFailedStatuses.
+func (mr *MockPlatformMockRecorder) ListFailedStatuses(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailedStatuses", reflect.TypeOf((*MockPlatform)(nil).ListFailedStatuses), arg0, arg1, arg2, arg3, arg4)
+}
+
 // ListIssuesByAuthor mocks base method.
 func (m *MockPlatform) ListIssuesByAuthor(arg0, arg1 string, arg2 interface{}) ([]*platforms.Issue, error) {
 	m.ctrl.T.Helper()
@@ -180,6 +254,21 @@ func (mr *MockPlatformMockRecorder) ListIssuesByAuthor(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIssuesByAuthor", reflect.TypeOf((*MockPlatform)(nil).ListIssuesByAuthor), arg0, arg1, arg2)
 }
 
+// ListReleaseAssets mocks base method.
+func (m *MockPlatform) ListReleaseAssets(arg0, arg1 string, arg2 *platforms.Release) ([]*platforms.ReleaseAsset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReleaseAssets", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*platforms.ReleaseAsset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReleaseAssets indicates an expected call of ListReleaseAssets.
+func (mr *MockPlatformMockRecorder) ListReleaseAssets(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleaseAssets", reflect.TypeOf((*MockPlatform)(nil).ListReleaseAssets), arg0, arg1, arg2)
+}
+
 // ListReleases mocks base method.
 func (m *MockPlatform) ListReleases(arg0, arg1 string) ([]*platforms.Release, error) {
 	m.ctrl.T.Helper()
@@ -195,6 +284,21 @@ func (mr *MockPlatformMockRecorder) ListReleases(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleases", reflect.TypeOf((*MockPlatform)(nil).ListReleases), arg0, arg1)
 }
 
+// ListRequiredStatuses mocks base method.
+func (m *MockPlatform) ListRequiredStatuses(arg0, arg1 string, arg2 *platforms.Release) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRequiredStatuses", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRequiredStatuses indicates an expected call of ListRequiredStatuses.
+func (mr *MockPlatformMockRecorder) ListRequiredStatuses(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRequiredStatuses", reflect.TypeOf((*MockPlatform)(nil).ListRequiredStatuses), arg0, arg1, arg2)
+}
+
 // ListStatuses mocks base method.
 func (m *MockPlatform) ListStatuses(arg0, arg1, arg2 string) ([]*platforms.Status, error) {
 	m.ctrl.T.Helper()
@@ -210,6 +314,21 @@ func (mr *MockPlatformMockRecorder) ListStatuses(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatuses", reflect.TypeOf((*MockPlatform)(nil).ListStatuses), arg0, arg1, arg2)
 }
 
+// ListTopics mocks base method.
+func (m *MockPlatform) ListTopics(arg0, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTopics", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTopics indicates an expected call of ListTopics.
+func (mr *MockPlatformMockRecorder) ListTopics(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTopics", reflect.TypeOf((*MockPlatform)(nil).ListTopics), arg0, arg1)
+}
+
 // PublishRelease mocks base method.
 func (m *MockPlatform) PublishRelease(arg0, arg1 string, arg2 *platforms.Release) (bool, error) {
 	m.ctrl.T.Helper()
@@ -226,18 +345,76 @@ func (mr *MockPlatformMockRecorder) PublishRelease(arg0, arg1, arg2 interface{})
 }
 
 // ReadFile mocks base method.
-func (m *MockPlatform) ReadFile(arg0, arg1, arg2 string) (io.Reader, error) {
+func (m *MockPlatform) ReadFile(arg0, arg1, arg2, arg3 string) (io.Reader, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReadFile", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ReadFile", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(io.Reader)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ReadFile indicates an expected call of ReadFile.
-func (mr *MockPlatformMockRecorder) ReadFile(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockPlatformMockRecorder) ReadFile(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadFile", reflect.TypeOf((*MockPlatform)(nil).ReadFile), arg0, arg1, arg2, arg3)
+}
+
+// RetryStatus mocks base method.
+func (m *MockPlatform) RetryStatus(arg0, arg1 string, arg2 *platforms.Status) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryStatus indicates an expected call of RetryStatus.
+func (mr *MockPlatformMockRecorder) RetryStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryStatus", reflect.TypeOf((*MockPlatform)(nil).RetryStatus), arg0, arg1, arg2)
+}
+
+// SearchIssues mocks base method.
+func (m *MockPlatform) SearchIssues(arg0, arg1 string, arg2 *platforms.IssueQuery) ([]*platforms.Issue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchIssues", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*platforms.Issue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchIssues indicates an expected call of SearchIssues.
+func (mr *MockPlatformMockRecorder) SearchIssues(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchIssues", reflect.TypeOf((*MockPlatform)(nil).SearchIssues), arg0, arg1, arg2)
+}
+
+// TriggerWorkflow mocks base method.
+func (m *MockPlatform) TriggerWorkflow(arg0, arg1 string, arg2 *platforms.Release, arg3 *platforms.Workflow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriggerWorkflow", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TriggerWorkflow indicates an expected call of TriggerWorkflow.
+func (mr *MockPlatformMockRecorder) TriggerWorkflow(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerWorkflow", reflect.TypeOf((*MockPlatform)(nil).TriggerWorkflow), arg0, arg1, arg2, arg3)
+}
+
+// UnpublishRelease mocks base method.
+func (m *MockPlatform) UnpublishRelease(arg0, arg1 string, arg2 *platforms.Release) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpublishRelease", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpublishRelease indicates an expected call of UnpublishRelease.
+func (mr *MockPlatformMockRecorder) UnpublishRelease(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadFile", reflect.TypeOf((*MockPlatform)(nil).ReadFile), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpublishRelease", reflect.TypeOf((*MockPlatform)(nil).UnpublishRelease), arg0, arg1, arg2)
 }
 
 // UpdateFile mocks base method.
@@ -1,7 +1,10 @@
 package platforms
 
 import (
+	"errors"
 	"io"
+	"net/http"
+	"time"
 )
 
 const (
@@ -10,36 +13,113 @@ const (
 
 	// Completed status value
 	completedStatusValue = "completed"
+
+	// Failed status value (Gitlab)
+	failedStatusValue = "failed"
+
+	// Canceled status value (Gitlab)
+	canceledStatusValue = "canceled"
+
+	// SignatureTargetTag define that the signature of the release tag is
+	// verified
+	SignatureTargetTag = "tag"
+
+	// SignatureTargetCommit define that the signature of the release commit is
+	// verified
+	SignatureTargetCommit = "commit"
+
+	// Neutral state value (Github)
+	neutralStateValue = "neutral"
+
+	// Skipped state value (Github)
+	skippedStateValue = "skipped"
+
+	// assetDownloadTimeout is the maximum duration of a release asset
+	// download, including reading its content
+	assetDownloadTimeout = 5 * time.Minute
 )
 
+// ErrFileNotFound is returned by ReadFile when the file doesn't exist in the
+// repository
+var ErrFileNotFound = errors.New("file not found")
+
+// assetClient is the HTTP client used to download release assets, a stalled
+// download is aborted after assetDownloadTimeout
+var assetClient = &http.Client{Timeout: assetDownloadTimeout}
+
 // Platform interface Github and Gitlab
 //
 //go:generate go run github.com/golang/mock/mockgen -destination mocks/platforms_mock.go -package mock_platforms github.com/fikaworks/grgate/pkg/platforms Platform
 type Platform interface {
-	CheckAllStatusSucceeded(string, string, string, []string) (bool, error)
+	CheckAllStatusSucceeded(string, string, string, []string, map[string]*StatusSource) (bool, error)
 	CreateFile(string, string, string, string, string, string) error
 	UpdateFile(string, string, string, string, string, string) error
 	CreateIssue(string, string, *Issue) error
 	CreateRelease(string, string, *Release) (*Release, error)
 	CreateRepository(string, string, string) error
 	CreateStatus(string, string, *Status) error
+	DeleteRelease(string, string, *Release) error
 	DeleteRepository(string, string) error
+	DownloadReleaseAsset(string, string, *ReleaseAsset) (io.ReadCloser, error)
+	GetCommit(string, string, string) (*Commit, error)
+	GetSignature(string, string, *Release, string) (*Signature, error)
 	GetStatus(string, string, string, string) (*Status, error)
 	ListDraftReleases(string, string) ([]*Release, error)
+	ListFailedStatuses(string, string, string, []string, map[string]*StatusSource) ([]string, error)
 	ListIssuesByAuthor(string, string, interface{}) ([]*Issue, error)
+	ListReleaseAssets(string, string, *Release) ([]*ReleaseAsset, error)
 	ListReleases(string, string) ([]*Release, error)
+	ListRequiredStatuses(string, string, *Release) ([]string, error)
 	ListStatuses(string, string, string) ([]*Status, error)
+	ListTopics(string, string) ([]string, error)
 	PublishRelease(string, string, *Release) (bool, error)
-	ReadFile(string, string, string) (io.Reader, error)
+	ReadFile(string, string, string, string) (io.Reader, error)
+	RetryStatus(string, string, *Status) error
+	SearchIssues(string, string, *IssueQuery) ([]*Issue, error)
+	TriggerWorkflow(string, string, *Release, *Workflow) error
+	UnpublishRelease(string, string, *Release) (bool, error)
 	UpdateIssue(string, string, *Issue) error
 	UpdateRelease(string, string, *Release) error
 }
 
+// Commit contains the metadata of a commit
+type Commit struct {
+	// Sha of the commit
+	Sha string
+
+	// Message of the commit
+	Message string
+
+	// Author is the name of the commit author
+	Author string
+
+	// AuthorEmail is the email of the commit author
+	AuthorEmail string
+
+	// Date the commit was authored
+	Date time.Time
+}
+
 // Issue contains the GRGate dashboard issue informations
 type Issue struct {
 	ID    interface{}
 	Title string
 	Body  string
+	URL   string
+}
+
+// IssueQuery define the criteria used to search open issues, all the
+// criteria must match
+type IssueQuery struct {
+	// Label the issues must have
+	Label string
+
+	// Milestone title the issues must be attached to
+	Milestone string
+
+	// PullRequests include pull requests (Github) or merge requests (Gitlab)
+	// in the results
+	PullRequests bool
 }
 
 // Release represent a release regarding the platform
@@ -65,6 +145,63 @@ type Release struct {
 	// Draft represent the state of the release. For Gitlab it translates to a
 	// future release
 	Draft bool
+
+	// PublishedAt is the time the release has been published, zero if the
+	// release is a draft
+	PublishedAt time.Time
+
+	// CreatedAt is the time the release has been created
+	CreatedAt time.Time
+}
+
+// ReleaseAsset represent a file attached to a release, for Gitlab it
+// translates to a release link
+type ReleaseAsset struct {
+	// ID of the asset, Github use an int64, Gitlab use an int
+	ID interface{}
+
+	// Name of the asset
+	Name string
+
+	// URL to download the asset from
+	URL string
+}
+
+// Signature contains the signature verification of a tag or a commit
+type Signature struct {
+	// Verified is true if the platform verified the signature
+	Verified bool
+
+	// Reason given by the platform for the verification result
+	Reason string
+
+	// KeyID is the long key ID of the key used to sign, only provided by
+	// Gitlab. It is replaced by the key fingerprint when the signature is
+	// verified with a keyring
+	KeyID string
+
+	// Signer is the email of the key owner, only provided by Gitlab
+	Signer string
+
+	// Payload is the signed content, only provided by Github
+	Payload string
+
+	// Signature is the armored signature, only provided by Github
+	Signature string
+}
+
+// Workflow represent a workflow to dispatch, for Gitlab it translates to a
+// pipeline
+type Workflow struct {
+	// Name of the workflow file (Github only), ie: e2e.yaml
+	Name string
+
+	// Ref to run the workflow against, default to the release target
+	// commitish on Github and to the release tag on Gitlab
+	Ref string
+
+	// Inputs of the workflow, for Gitlab they translate to pipeline variables
+	Inputs map[string]string
 }
 
 // Status contains commit status informations
@@ -72,6 +209,10 @@ type Status struct {
 	// CommitSha
 	CommitSha string
 
+	// ID of the status, Github use the check run ID (int64), Gitlab use the
+	// job ID (int)
+	ID interface{}
+
 	// Name of the status
 	Name string
 
@@ -84,3 +225,32 @@ type Status struct {
 	// For Gitlab must be one of: pending, running, success, failed or cancelled
 	Status string
 }
+
+// StatusSource define the trusted origin of a status. Empty fields are not
+// checked, fields which are not supported by a platform never match
+type StatusSource struct {
+	// App is the ID or the slug of the Github App which created the check run
+	// (Github only)
+	App string
+
+	// Creator is the username of the user who created the commit status
+	// (Gitlab only)
+	Creator string
+
+	// PipelineSource is the source of the pipeline the commit status belong
+	// to, ie: push, web, schedule, trigger or external (Gitlab only)
+	PipelineSource string
+}
+
+// IsFailed returns true if the status completed without succeeding
+func (s *Status) IsFailed() bool {
+	switch s.Status {
+	case completedStatusValue:
+		return s.State != successStatusValue &&
+			s.State != neutralStateValue &&
+			s.State != skippedStateValue
+	case failedStatusValue, canceledStatusValue:
+		return true
+	}
+	return false
+}
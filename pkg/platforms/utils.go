@@ -12,3 +12,13 @@ func mapGithubStatusToGitlabStatus(status string) string {
 
 	return status
 }
+
+// appendUnique append a value to a list if it is not already present
+func appendUnique(list []string, value string) []string {
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// handleReload reload the config on SIGHUP or when the config file changes
+// until stop is closed
+func (s *Server) handleReload(stop chan struct{}) {
+	if s.Config.Reload == nil {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	changed := make(chan struct{}, 1)
+	if s.Config.ConfigFile != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			log.Error().Err(err).Msg("Couldn't watch config file, config is only reloaded on SIGHUP")
+		} else {
+			defer watcher.Close()
+			go watchConfigFile(watcher, s.Config.ConfigFile, changed)
+		}
+	}
+
+	for {
+		select {
+		case <-hup:
+			log.Info().Msg("Received SIGHUP, reloading config")
+			s.reload()
+		case <-changed:
+			log.Info().Msgf("Config file %s changed, reloading config",
+				s.Config.ConfigFile)
+			s.reload()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// watchConfigFile notify changed when the config file is written or replaced.
+// The directory is watched instead of the file so that files replaced by
+// editors or mounted from a Kubernetes ConfigMap (symlink swap) are detected
+func watchConfigFile(watcher *fsnotify.Watcher, configFile string,
+	changed chan struct{}) {
+	configFile = filepath.Clean(configFile)
+	realConfigFile, _ := filepath.EvalSymlinks(configFile)
+
+	if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+		log.Error().Err(err).Msg("Couldn't watch config file, config is only reloaded on SIGHUP")
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			currentConfigFile, _ := filepath.EvalSymlinks(configFile)
+			written := filepath.Clean(event.Name) == configFile &&
+				event.Op&(fsnotify.Write|fsnotify.Create) != 0
+			replaced := currentConfigFile != "" && currentConfigFile != realConfigFile
+			if !written && !replaced {
+				continue
+			}
+			realConfigFile = currentConfigFile
+
+			// a pending notification already trigger a reload
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error().Err(err).Msg("Error watching config file")
+		}
+	}
+}
+
+// reload the config, the current config is kept if the new config is invalid.
+// The engine is updated by Reload, then the webhook secret is replaced and the
+// worker pool resized. These steps are not applied atomically, the ordering is
+// safe because each of them is independent:
+//   - the engine swap its platform and main config under its own lock, a job
+//     capture both when it is created so it never mixes the previous and the
+//     reloaded config. Jobs already queued are processed with the previous
+//     config
+//   - an event received between the engine update and the secret update is
+//     validated with the previous secret, as if it had been received before
+//     the reload
+//   - the worker pool size doesn't affect how a job is processed
+//
+// The log level is applied, the log format and the server addresses require a
+// restart
+func (s *Server) reload() {
+	config, err := s.Config.Reload()
+	if err != nil {
+		log.Error().Err(err).Msg("Couldn't reload config, keeping the current config")
+		return
+	}
+
+	s.Webhook.Update(config.WebhookSecret)
+	s.WorkerPool.Resize(config.Workers)
+	zerolog.SetGlobalLevel(config.LogLevel)
+
+	if config.ListenAddr != s.Config.ListenAddr ||
+		config.MetricsAddr != s.Config.MetricsAddr ||
+		config.ProbeAddr != s.Config.ProbeAddr {
+		log.Warn().Msg("Server addresses changed, restart the server to apply them")
+	}
+
+	if config.LogFormat != s.Config.LogFormat {
+		log.Warn().Msg("Log format changed, restart the server to apply it")
+	}
+
+	log.Info().Msg("Config reloaded")
+}
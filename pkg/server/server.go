@@ -13,7 +13,6 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
-	"github.com/fikaworks/grgate/pkg/platforms"
 	"github.com/fikaworks/grgate/pkg/workers"
 )
 
@@ -29,13 +28,25 @@ const (
 
 // Config hold configuration to run a server
 type Config struct {
+	// ConfigFile is watched and the config reloaded when it changes, if empty
+	// the config is only reloaded on SIGHUP
+	ConfigFile string
+
+	// Engine create the jobs of webhook events
+	Engine *workers.Engine
+
 	ListenAddr    string
+	LogFormat     string
+	LogLevel      zerolog.Level
 	Logger        zerolog.Logger
 	MetricsAddr   string
-	Platform      platforms.Platform
 	ProbeAddr     string
 	WebhookSecret string
 	Workers       int
+
+	// Reload update the engine and returns the server config from the
+	// reloaded main config, if nil the config is not reloaded
+	Reload func() (*Config, error)
 }
 
 // Server hold a server instance
@@ -45,6 +56,7 @@ type Server struct {
 	MainServer    *http.Server
 	MetricsServer *http.Server
 	ProbeServer   *http.Server
+	Webhook       *WebhookHandler
 	WorkerPool    *workers.WorkerPool
 }
 
@@ -75,7 +87,7 @@ func NewServer(config *Config) *Server {
 	cancelWorker := make(chan struct{})
 	workerPool := workers.NewWorkerPool(config.Workers, cancelWorker)
 
-	webhook := NewWebhookHandler(config.Platform, config.WebhookSecret,
+	webhook := NewWebhookHandler(config.Engine, config.WebhookSecret,
 		workerPool.JobQueue)
 
 	e.POST("/github/webhook", webhook.GithubHandler)
@@ -92,6 +104,7 @@ func NewServer(config *Config) *Server {
 		MainServer:    mainServer,
 		MetricsServer: metricsServer,
 		ProbeServer:   probeServer,
+		Webhook:       webhook,
 		WorkerPool:    workerPool,
 		CancelWorker:  cancelWorker,
 	}
@@ -112,8 +125,13 @@ func (s *Server) Start() {
 	go s.serveHTTP()
 	go s.serveProbe()
 
+	stopReload := make(chan struct{})
+	go s.handleReload(stopReload)
+
 	<-quit
 
+	close(stopReload)
+
 	log.Info().Msg("Shutting down worker pool...")
 	close(s.CancelWorker)
 
@@ -1,32 +1,58 @@
 package server
 
 import (
+	"sync"
+
 	"github.com/rs/zerolog/log"
 
-	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
 	"github.com/fikaworks/grgate/pkg/workers"
 )
 
 // WebhookHandler hold webhook configuration
 type WebhookHandler struct {
-	JobQueue      chan *workers.Job
-	Platform      platforms.Platform
-	WebhookSecret string
+	Engine   *workers.Engine
+	JobQueue chan *workers.Job
+
+	// mu protects the webhook secret which is replaced when the config is
+	// reloaded
+	mu            sync.RWMutex
+	webhookSecret string
 }
 
 // NewWebhookHandler returns an instance of WebhookHandler
-func NewWebhookHandler(platform platforms.Platform, webhookSecret string, jobQueue chan *workers.Job) *WebhookHandler {
+func NewWebhookHandler(engine *workers.Engine, webhookSecret string, jobQueue chan *workers.Job) *WebhookHandler {
 	return &WebhookHandler{
-		Platform:      platform,
-		WebhookSecret: webhookSecret,
+		Engine:        engine,
+		webhookSecret: webhookSecret,
 		JobQueue:      jobQueue,
 	}
 }
 
+// Update replace the webhook secret used to validate new events
+func (h *WebhookHandler) Update(webhookSecret string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.webhookSecret = webhookSecret
+}
+
+// WebhookSecret returns the secret used to validate webhook requests
+func (h *WebhookHandler) WebhookSecret() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.webhookSecret
+}
+
 func (h *WebhookHandler) processEvent(owner, repository string) {
+	if !h.Engine.IsRepositoryAllowed(owner, repository) {
+		return
+	}
+
 	log.Debug().Msgf("Creating new job for %s/%s", owner, repository)
 
-	job, err := workers.NewJob(h.Platform, owner, repository)
+	job, err := h.Engine.NewJob(owner, repository)
 	if err != nil {
 		log.Error().Err(err).Msgf("Could not create job for %s/%s", owner, repository)
 		return
@@ -34,3 +60,14 @@ func (h *WebhookHandler) processEvent(owner, repository string) {
 
 	h.JobQueue <- job
 }
+
+// processDependents create a job for each repository depending on the provided
+// repository, used when an upstream release is published
+func (h *WebhookHandler) processDependents(owner, repository string) {
+	for _, dependent := range h.Engine.Dependents(owner, repository) {
+		log.Debug().Msgf("Release published in %s/%s, processing dependent %s",
+			owner, repository, dependent)
+		h.processEvent(utils.GetRepositoryOrganization(dependent),
+			utils.GetRepositoryName(dependent))
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v43/github"
 	"github.com/labstack/echo/v4"
@@ -10,7 +11,7 @@ import (
 
 func (h *WebhookHandler) GithubHandler(c echo.Context) error {
 	r := c.Request()
-	payload, err := github.ValidatePayload(r, []byte(h.WebhookSecret))
+	payload, err := github.ValidatePayload(r, []byte(h.WebhookSecret()))
 	if err != nil {
 		log.Error().Err(err).Msg("Error validating request body")
 		return c.NoContent(http.StatusForbidden)
@@ -31,6 +32,8 @@ func (h *WebhookHandler) GithubHandler(c echo.Context) error {
 		h.processGithubCheckRunEvent(event)
 	case *github.ReleaseEvent:
 		h.processGithubReleaseEvent(event)
+	case *github.PushEvent:
+		h.processGithubPushEvent(event)
 	default:
 		log.Info().Msgf("Event type %s is not supported", github.WebHookType(r))
 	}
@@ -40,7 +43,9 @@ func (h *WebhookHandler) GithubHandler(c echo.Context) error {
 
 func (h *WebhookHandler) processGithubStatusEvent(event *github.StatusEvent) {
 	log.Debug().Msg("Received webhook event StatusEvent")
-	if event.State != nil && *event.State == "success" {
+	// failing statuses are also processed so that published releases can be
+	// rolled back
+	if event.State != nil && *event.State != "pending" {
 		h.processEvent(*event.Repo.Owner.Login, *event.Repo.Name)
 	}
 }
@@ -61,7 +66,40 @@ func (h *WebhookHandler) processGithubCheckRunEvent(event *github.CheckRunEvent)
 
 func (h *WebhookHandler) processGithubReleaseEvent(event *github.ReleaseEvent) {
 	log.Debug().Msg("Received webhook event ReleaseEvent")
-	if event.Action != nil && (*event.Action == "created" || *event.Action == "edited") {
+	if event.Action == nil {
+		return
+	}
+	switch *event.Action {
+	case "created", "edited":
 		h.processEvent(*event.Repo.Owner.Login, *event.Repo.Name)
+	case "published":
+		h.processDependents(*event.Repo.Owner.Login, *event.Repo.Name)
+	}
+}
+
+// processGithubPushEvent invalidate the cached config files changed by a push
+func (h *WebhookHandler) processGithubPushEvent(event *github.PushEvent) {
+	log.Debug().Msg("Received webhook event PushEvent")
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repository := event.GetRepo().GetName()
+	branch := strings.TrimPrefix(event.GetRef(), "refs/heads/")
+
+	// the payload doesn't list all the commits of large pushes
+	if event.GetSize() > len(event.Commits) {
+		h.Engine.InvalidateRepoConfigCache(owner, repository, branch,
+			event.GetRepo().GetDefaultBranch(), nil)
+		return
+	}
+
+	var paths []string
+	for _, commit := range event.Commits {
+		paths = append(paths, commit.Added...)
+		paths = append(paths, commit.Modified...)
+		paths = append(paths, commit.Removed...)
+	}
+
+	if len(paths) > 0 {
+		h.Engine.InvalidateRepoConfigCache(owner, repository, branch,
+			event.GetRepo().GetDefaultBranch(), paths)
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
@@ -12,10 +13,14 @@ import (
 	"github.com/fikaworks/grgate/pkg/utils"
 )
 
+// gitlabEventTimeLayout is the layout of dates sent in Gitlab webhook events
+const gitlabEventTimeLayout = "2006-01-02 15:04:05 MST"
+
 var (
 	gitlabEvents []gitlab.EventType = []gitlab.EventType{
 		gitlab.EventTypeRelease,
 		gitlab.EventTypePipeline,
+		gitlab.EventTypePush,
 	}
 )
 
@@ -32,7 +37,7 @@ func (h *WebhookHandler) GitlabHandler(c echo.Context) error {
 	}()
 
 	signature := r.Header.Get("X-Gitlab-Token")
-	if signature != h.WebhookSecret {
+	if signature != h.WebhookSecret() {
 		log.Error().Msg("Token validation failed")
 		return c.NoContent(http.StatusForbidden)
 	}
@@ -69,6 +74,8 @@ func (h *WebhookHandler) GitlabHandler(c echo.Context) error {
 		h.processGitlabReleaseEvent(*parsedBody.(*gitlab.ReleaseEvent))
 	case gitlab.EventTypePipeline:
 		h.processGitlabPipelineEvent(*parsedBody.(*gitlab.PipelineEvent))
+	case gitlab.EventTypePush:
+		h.processGitlabPushEvent(*parsedBody.(*gitlab.PushEvent))
 	default:
 		log.Info().Msgf("Event type %s is not supported", eventType)
 	}
@@ -80,6 +87,12 @@ func (h *WebhookHandler) processGitlabReleaseEvent(event gitlab.ReleaseEvent) {
 	owner := utils.GetRepositoryOrganization(event.Project.PathWithNamespace)
 	repository := utils.GetRepositoryName(event.Project.PathWithNamespace)
 	h.processEvent(owner, repository)
+
+	// releases with a release date in the past are published
+	releasedAt, err := time.Parse(gitlabEventTimeLayout, event.ReleasedAt)
+	if err == nil && !releasedAt.After(time.Now()) {
+		h.processDependents(owner, repository)
+	}
 }
 
 func (h *WebhookHandler) processGitlabPipelineEvent(event gitlab.PipelineEvent) {
@@ -88,6 +101,32 @@ func (h *WebhookHandler) processGitlabPipelineEvent(event gitlab.PipelineEvent)
 	h.processEvent(owner, repository)
 }
 
+// processGitlabPushEvent invalidate the cached config files changed by a push
+func (h *WebhookHandler) processGitlabPushEvent(event gitlab.PushEvent) {
+	owner := utils.GetRepositoryOrganization(event.Project.PathWithNamespace)
+	repository := utils.GetRepositoryName(event.Project.PathWithNamespace)
+	branch := strings.TrimPrefix(event.Ref, "refs/heads/")
+
+	// the payload doesn't list all the commits of large pushes
+	if event.TotalCommitsCount > len(event.Commits) {
+		h.Engine.InvalidateRepoConfigCache(owner, repository, branch,
+			event.Project.DefaultBranch, nil)
+		return
+	}
+
+	var paths []string
+	for _, commit := range event.Commits {
+		paths = append(paths, commit.Added...)
+		paths = append(paths, commit.Modified...)
+		paths = append(paths, commit.Removed...)
+	}
+
+	if len(paths) > 0 {
+		h.Engine.InvalidateRepoConfigCache(owner, repository, branch,
+			event.Project.DefaultBranch, paths)
+	}
+}
+
 func isGitlabEventSubscribed(event gitlab.EventType, events []gitlab.EventType) bool {
 	for _, e := range events {
 		if event == e {
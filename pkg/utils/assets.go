@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"strings"
+)
+
+// MissingAssets returns the patterns which do not match any of the provided
+// asset names
+func MissingAssets(patterns, assetNames []string) (missing []string, err error) {
+	for _, pattern := range patterns {
+		found := false
+		for _, name := range assetNames {
+			match, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid asset pattern \"%s\": %w", pattern, err)
+			}
+			if match {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, pattern)
+		}
+	}
+	return
+}
+
+// ParseChecksums parse a checksum file generated by sha256sum or sha512sum and
+// returns the checksums indexed by file name
+func ParseChecksums(r io.Reader) (checksums map[string]string, err error) {
+	checksums = make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid checksum line \"%s\"", line)
+		}
+
+		// binary mode is marked with a * in front of the file name
+		name := strings.TrimPrefix(fields[1], "*")
+		checksums[name] = strings.ToLower(fields[0])
+	}
+
+	return checksums, scanner.Err()
+}
+
+// VerifyChecksum compute the checksum of the content and compare it with the
+// expected checksum. The hash algorithm (sha256 or sha512) is detected from
+// the length of the expected checksum
+func VerifyChecksum(content io.Reader, expected string) (bool, error) {
+	var h hash.Hash
+	switch len(expected) {
+	case hex.EncodedLen(sha256.Size):
+		h = sha256.New()
+	case hex.EncodedLen(sha512.Size):
+		h = sha512.New()
+	default:
+		return false, fmt.Errorf("unsupported checksum \"%s\"", expected)
+	}
+
+	if _, err := io.Copy(h, content); err != nil {
+		return false, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)) == expected, nil
+}
@@ -0,0 +1,76 @@
+//go:build unit
+
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestMissingAssets(t *testing.T) {
+	expected := []string{"*.zip"}
+	result, err := MissingAssets([]string{"*_linux_amd64.tar.gz", "*.zip"},
+		[]string{"grgate_linux_amd64.tar.gz", "checksums.txt"})
+	if err != nil {
+		t.Errorf("Error not expected: %#v", err)
+	}
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	if _, err := MissingAssets([]string{"[a-"}, []string{"a"}); err == nil {
+		t.Errorf("Expected error with invalid pattern")
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	expected := map[string]string{
+		"grgate_linux_amd64.tar.gz": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		"grgate_darwin_arm64.zip":   "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752",
+	}
+	result, err := ParseChecksums(strings.NewReader(`# checksums
+9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08  grgate_linux_amd64.tar.gz
+60303AE22B998861BCE3B28F33EEC1BE758A213C86C93C076DBE9F558C11C752 *grgate_darwin_arm64.zip
+`))
+	if err != nil {
+		t.Errorf("Error not expected: %#v", err)
+	}
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	if _, err := ParseChecksums(strings.NewReader("invalid")); err == nil {
+		t.Errorf("Expected error with invalid checksum file")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	testCases := []struct {
+		content  string
+		checksum string
+		expected bool
+	}{
+		{
+			content:  "test",
+			checksum: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			expected: true,
+		},
+		{
+			content:  "tampered",
+			checksum: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		result, err := VerifyChecksum(strings.NewReader(test.content), test.checksum)
+		if err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+		if result != test.expected {
+			t.Errorf("content %s, got %t, expected %t", test.content, result, test.expected)
+		}
+	}
+}
@@ -7,9 +7,30 @@ import (
 
 // DashboardData hold issue data used to populate the issue dashboard template
 type DashboardData struct {
-	Errors            []string
-	Enabled           bool
-	LastExecutionTime string
+	BlockedReleases    []*DashboardRelease
+	Errors             []string
+	Enabled            bool
+	LastExecutionTime  string
+	QueuedReleases     []*DashboardRelease
+	RetriedStatuses    []*DashboardRetry
+	RolledBackReleases []*DashboardRelease
+	TimedOutReleases   []*DashboardRelease
+}
+
+// DashboardRelease hold information about a release displayed in the issue
+// dashboard
+type DashboardRelease struct {
+	Name   string
+	Reason string
+	Tag    string
+}
+
+// DashboardRetry hold information about a failed status retried by GRGate
+type DashboardRetry struct {
+	Attempts int
+	Retries  int
+	Status   string
+	Tag      string
 }
 
 func RenderDashboard(tpl string, data *DashboardData) (output string, err error) {
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DependencyStatePublished require the upstream release to be published
+	DependencyStatePublished = "published"
+
+	// DependencyStateDraft only require the upstream release to exist, either
+	// as draft or published release
+	DependencyStateDraft = "draft"
+)
+
+// Dependency represent a release dependency to another repository, ie:
+// "backend >= v2.3.0 published"
+type Dependency struct {
+	Owner      string
+	Repository string
+	Operator   string
+	Version    string
+	State      string
+}
+
+// Regexp matching a dependency: "[owner/]repository [operator version] [state]"
+var dependencyRegexp = regexp.MustCompile(`^\s*(?:([a-zA-Z-_0-9.]+)/)?([a-zA-Z-_0-9.]+)` +
+	`(?:\s*(==|=|!=|>=|<=|>|<)\s*(\S+))?(?:\s+(published|draft))?\s*$`)
+
+// Regexp matching a semantic version with an optional "v" prefix
+var versionRegexp = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// String returns the dependency as defined in the config
+func (d *Dependency) String() string {
+	s := d.Owner + "/" + d.Repository
+	if d.Operator != "" {
+		s += " " + d.Operator + " " + d.Version
+	}
+	return s + " " + d.State
+}
+
+// ParseDependency parse a dependency defined as "[owner/]repository [operator
+// version] [published|draft]". If the owner is omitted, the default owner is
+// used. The state default to published
+func ParseDependency(input, defaultOwner string) (dependency *Dependency, err error) {
+	match := dependencyRegexp.FindStringSubmatch(input)
+	if match == nil {
+		return nil, fmt.Errorf("invalid dependency \"%s\"", input)
+	}
+
+	dependency = &Dependency{
+		Owner:      match[1],
+		Repository: match[2],
+		Operator:   match[3],
+		Version:    match[4],
+		State:      match[5],
+	}
+
+	if dependency.Owner == "" {
+		dependency.Owner = defaultOwner
+	}
+
+	if dependency.State == "" {
+		dependency.State = DependencyStatePublished
+	}
+
+	if dependency.Operator != "" {
+		if _, err = parseVersion(dependency.Version); err != nil {
+			return nil, fmt.Errorf("invalid dependency \"%s\": %w", input, err)
+		}
+	}
+
+	return dependency, nil
+}
+
+// IsSatisfiedBy returns true if the provided tag satisfy the version
+// constraint of the dependency. Tags which are not a valid version never
+// satisfy a constraint
+func (d *Dependency) IsSatisfiedBy(tag string) bool {
+	if d.Operator == "" {
+		return true
+	}
+
+	result, err := CompareVersions(tag, d.Version)
+	if err != nil {
+		return false
+	}
+
+	switch d.Operator {
+	case "=", "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+
+	return false
+}
+
+// version hold the parsed values of a semantic version
+type version struct {
+	numbers    [3]int
+	prerelease string
+}
+
+func parseVersion(input string) (v *version, err error) {
+	match := versionRegexp.FindStringSubmatch(input)
+	if match == nil {
+		return nil, fmt.Errorf("invalid version \"%s\"", input)
+	}
+
+	v = &version{prerelease: match[4]}
+	for i := 0; i < len(v.numbers); i++ {
+		if match[i+1] == "" {
+			continue
+		}
+		if v.numbers[i], err = strconv.Atoi(match[i+1]); err != nil {
+			return nil, fmt.Errorf("invalid version \"%s\": %w", input, err)
+		}
+	}
+
+	return v, nil
+}
+
+// CompareVersions compare two semantic versions and returns -1 if a < b, 0 if
+// a == b and 1 if a > b. The "v" prefix and build metadata are ignored
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.numbers {
+		if va.numbers[i] != vb.numbers[i] {
+			if va.numbers[i] < vb.numbers[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	return comparePrereleases(va.prerelease, vb.prerelease), nil
+}
+
+// comparePrereleases compare prerelease identifiers as defined by the semver
+// specification, a version without prerelease has a higher precedence
+func comparePrereleases(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	pa := strings.Split(a, ".")
+	pb := strings.Split(b, ".")
+
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] == pb[i] {
+			continue
+		}
+
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+
+		switch {
+		case errA == nil && errB == nil:
+			if na < nb {
+				return -1
+			}
+			return 1
+		case errA == nil:
+			// numeric identifiers have lower precedence
+			return -1
+		case errB == nil:
+			return 1
+		case pa[i] < pb[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	switch {
+	case len(pa) < len(pb):
+		return -1
+	case len(pa) > len(pb):
+		return 1
+	}
+
+	return 0
+}
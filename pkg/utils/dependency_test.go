@@ -0,0 +1,103 @@
+//go:build unit
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestParseDependency(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    *Dependency
+		expectError bool
+	}{
+		{
+			input: "backend >= v2.3.0 published",
+			expected: &Dependency{
+				Owner:      "owner",
+				Repository: "backend",
+				Operator:   ">=",
+				Version:    "v2.3.0",
+				State:      DependencyStatePublished,
+			},
+		},
+		{
+			input: "fikaworks/backend<1.0.0-rc.1 draft",
+			expected: &Dependency{
+				Owner:      "fikaworks",
+				Repository: "backend",
+				Operator:   "<",
+				Version:    "1.0.0-rc.1",
+				State:      DependencyStateDraft,
+			},
+		},
+		{
+			input: "backend",
+			expected: &Dependency{
+				Owner:      "owner",
+				Repository: "backend",
+				State:      DependencyStatePublished,
+			},
+		},
+		{
+			input:       "backend >= latest",
+			expectError: true,
+		},
+		{
+			input:       "backend ~ v1.0.0",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := ParseDependency(tc.input, "owner")
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for input %s", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+			if diff := pretty.Compare(result, tc.expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDependencyIsSatisfiedBy(t *testing.T) {
+	testCases := []struct {
+		constraint string
+		tag        string
+		expected   bool
+	}{
+		{"backend >= v2.3.0", "v2.3.0", true},
+		{"backend >= v2.3.0", "v2.10.1", true},
+		{"backend >= v2.3.0", "v2.3.0-rc.1", false},
+		{"backend >= v2.3.0", "v2.2.9", false},
+		{"backend >= v2.3.0", "not-a-version", false},
+		{"backend == 1.2", "v1.2.0", true},
+		{"backend != v1.2.0", "v1.2.1", true},
+		{"backend > v1.0.0-alpha", "v1.0.0-alpha.1", true},
+		{"backend < v1.0.0-beta", "v1.0.0-alpha.10", true},
+		{"backend", "anything", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint+" "+tc.tag, func(t *testing.T) {
+			dependency, err := ParseDependency(tc.constraint, "owner")
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+			if result := dependency.IsSatisfiedBy(tc.tag); result != tc.expected {
+				t.Errorf("Expected %t, got %t", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// ExternalDecisionAllow allow the release to be published
+	ExternalDecisionAllow = "allow"
+
+	// ExternalDecisionDeny block the release
+	ExternalDecisionDeny = "deny"
+
+	// ExternalDecisionPending hold the release until the decision is made
+	ExternalDecisionPending = "pending"
+
+	// ExternalSignatureHeader is the header holding the HMAC SHA256 signature
+	// of the request body, formatted as "sha256=<hex digest>"
+	ExternalSignatureHeader = "X-GRGate-Signature-256"
+
+	// externalTimeout is the maximum duration of an external decision request
+	externalTimeout = 30 * time.Second
+)
+
+// ExternalDecision is the response of the external decision service
+type ExternalDecision struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// SignExternalPayload returns the HMAC SHA256 signature of a payload formatted
+// as "sha256=<hex digest>"
+func SignExternalPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RequestExternalDecision post the release data as JSON to the external
+// decision service and returns its decision. If a secret is provided, the
+// payload is signed
+func RequestExternalDecision(url, secret string,
+	request *ReleaseContext) (decision *ExternalDecision, err error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(ExternalSignatureHeader, SignExternalPayload(secret, payload))
+	}
+
+	client := &http.Client{Timeout: externalTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("external decision request failed with status %d",
+			resp.StatusCode)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return nil, fmt.Errorf("couldn't decode external decision: %w", err)
+	}
+
+	switch decision.Decision {
+	case ExternalDecisionAllow, ExternalDecisionDeny, ExternalDecisionPending:
+		return decision, nil
+	}
+
+	return nil, fmt.Errorf("unsupported external decision \"%s\"", decision.Decision)
+}
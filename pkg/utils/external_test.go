@@ -0,0 +1,96 @@
+//go:build unit
+
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestRequestExternalDecision(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get(ExternalSignatureHeader) != SignExternalPayload("secret", body) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			var request *ReleaseContext
+			if err := json.Unmarshal(body, &request); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			switch request.Release.Tag {
+			case "v1.2.3":
+				_, _ = w.Write([]byte(`{"decision":"deny","reason":"change freeze"}`))
+			case "v1.2.4":
+				_, _ = w.Write([]byte(`{"decision":"pending"}`))
+			default:
+				_, _ = w.Write([]byte(`{"decision":"maybe"}`))
+			}
+		}))
+	defer server.Close()
+
+	testCases := []struct {
+		name        string
+		tag         string
+		secret      string
+		expected    *ExternalDecision
+		expectError bool
+	}{
+		{
+			name:     "should return a signed request decision",
+			tag:      "v1.2.3",
+			secret:   "secret",
+			expected: &ExternalDecision{Decision: "deny", Reason: "change freeze"},
+		},
+		{
+			name:     "should return a pending decision",
+			tag:      "v1.2.4",
+			secret:   "secret",
+			expected: &ExternalDecision{Decision: "pending"},
+		},
+		{
+			name:        "should return an error if the decision is not supported",
+			tag:         "v1.2.5",
+			secret:      "secret",
+			expectError: true,
+		},
+		{
+			name:        "should return an error if the signature is invalid",
+			tag:         "v1.2.3",
+			secret:      "other",
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := RequestExternalDecision(server.URL, testCase.secret,
+				&ReleaseContext{
+					Owner:      "fikaworks",
+					Repository: "grgate",
+					Release:    &ReleaseContextRelease{Tag: testCase.tag},
+				})
+			if testCase.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %#v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+			if diff := pretty.Compare(result, testCase.expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prometheusTimeout is the maximum duration of a Prometheus query
+const prometheusTimeout = 30 * time.Second
+
+// prometheusResponse represent the response of the Prometheus instant query
+// API
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// prometheusSample represent a sample of an instant vector
+type prometheusSample struct {
+	Value [2]interface{} `json:"value"`
+}
+
+// QueryPrometheus run an instant query against a Prometheus compatible API and
+// returns the value of each returned sample. Only vector and scalar results
+// are supported
+func QueryPrometheus(apiURL, query string) (values []float64, err error) {
+	u, err := url.Parse(strings.TrimRight(apiURL, "/") + "/api/v1/query")
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"query": []string{query}}.Encode()
+
+	client := &http.Client{Timeout: prometheusTimeout}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response prometheusResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("couldn't decode response: %w", err)
+	}
+
+	if response.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s: %s", response.ErrorType,
+			response.Error)
+	}
+
+	switch response.Data.ResultType {
+	case "scalar":
+		var sample [2]interface{}
+		if err = json.Unmarshal(response.Data.Result, &sample); err != nil {
+			return nil, err
+		}
+		value, err := parseSampleValue(sample)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{value}, nil
+	case "vector":
+		var samples []prometheusSample
+		if err = json.Unmarshal(response.Data.Result, &samples); err != nil {
+			return nil, err
+		}
+		for _, sample := range samples {
+			value, err := parseSampleValue(sample.Value)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unsupported result type %s", response.Data.ResultType)
+}
+
+// parseSampleValue returns the value of a sample formatted as
+// [timestamp, "value"]
+func parseSampleValue(sample [2]interface{}) (float64, error) {
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value %v", sample[1])
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// CompareThreshold returns true if the value compared to the threshold with
+// the operator is true
+func CompareThreshold(value float64, operator string, threshold float64) (bool, error) {
+	switch operator {
+	case "<":
+		return value < threshold, nil
+	case "<=":
+		return value <= threshold, nil
+	case ">":
+		return value > threshold, nil
+	case ">=":
+		return value >= threshold, nil
+	case "==", "=":
+		return value == threshold, nil
+	case "!=":
+		return value != threshold, nil
+	}
+	return false, fmt.Errorf("unsupported operator \"%s\"", operator)
+}
@@ -0,0 +1,91 @@
+//go:build unit
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestQueryPrometheus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/query" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			switch r.URL.Query().Get("query") {
+			case "vector":
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+					`{"metric":{"pod":"a"},"value":[1700000000,"0.01"]},` +
+					`{"metric":{"pod":"b"},"value":[1700000000,"0.2"]}]}}`))
+			case "scalar":
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1700000000,"42"]}}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+			}
+		}))
+	defer server.Close()
+
+	testCases := []struct {
+		query       string
+		expected    []float64
+		expectError bool
+	}{
+		{query: "vector", expected: []float64{0.01, 0.2}},
+		{query: "scalar", expected: []float64{42}},
+		{query: "invalid", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			result, err := QueryPrometheus(server.URL, tc.query)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for query %s", tc.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+			if diff := pretty.Compare(result, tc.expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompareThreshold(t *testing.T) {
+	testCases := []struct {
+		value     float64
+		operator  string
+		threshold float64
+		expected  bool
+	}{
+		{0.01, "<", 0.05, true},
+		{0.05, "<", 0.05, false},
+		{0.05, "<=", 0.05, true},
+		{300, ">", 500, false},
+		{1, "==", 1, true},
+	}
+
+	for _, tc := range testCases {
+		result, err := CompareThreshold(tc.value, tc.operator, tc.threshold)
+		if err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+		if result != tc.expected {
+			t.Errorf("Expected %g %s %g to be %t", tc.value, tc.operator,
+				tc.threshold, tc.expected)
+		}
+	}
+
+	if _, err := CompareThreshold(1, "~", 1); err == nil {
+		t.Errorf("Expected error with unsupported operator")
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// notificationTimeout is the maximum duration of a notification request
+const notificationTimeout = 10 * time.Second
+
+// Notification hold information about a release sent to a notification URL.
+// The Text field make the payload compatible with Slack and Mattermost
+// incoming webhooks
+type Notification struct {
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+	Text       string `json:"text"`
+}
+
+// SendNotification post the notification as JSON to the provided URL
+func SendNotification(url string, notification *Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: notificationTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notification request failed with status %d",
+			resp.StatusCode)
+	}
+
+	return nil
+}
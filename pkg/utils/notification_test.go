@@ -0,0 +1,52 @@
+//go:build unit
+
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestSendNotification(t *testing.T) {
+	notification := &Notification{
+		Owner:      "owner",
+		Repository: "repository",
+		Tag:        "v1.2.3",
+		Reason:     "timeout",
+		Text:       "release blocked",
+	}
+
+	t.Run("should post the notification as JSON", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				var received Notification
+				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+					t.Errorf("Error not expected: %#v", err)
+				}
+				if diff := pretty.Compare(&received, notification); diff != "" {
+					t.Errorf("diff: (-got +want)\n%s", diff)
+				}
+			}))
+		defer server.Close()
+
+		if err := SendNotification(server.URL, notification); err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+	})
+
+	t.Run("should fail if the request is rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+		defer server.Close()
+
+		if err := SendNotification(server.URL, notification); err == nil {
+			t.Errorf("Expected error with rejected request")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// PluginStateSuccess allow the release to be published
+	PluginStateSuccess = "success"
+
+	// PluginStatePending hold the release until the plugin succeed
+	PluginStatePending = "pending"
+
+	// PluginStateFailure block the release
+	PluginStateFailure = "failure"
+
+	// pluginPath is the PATH environment variable passed to plugins
+	pluginPath = "/usr/local/bin:/usr/bin:/bin"
+
+	// pluginStderrLimit is the maximum length of stderr reported in errors
+	pluginStderrLimit = 256
+)
+
+// PluginVerdict is the verdict written by a plugin to its stdout
+type PluginVerdict struct {
+	State   string `json:"state"`
+	Message string `json:"message"`
+}
+
+// RunPlugin execute a plugin with the release context written as JSON to its
+// stdin and returns the verdict it wrote as JSON to stdout. The plugin run in
+// a temporary working directory with an environment limited to the provided
+// env, formatted as "KEY=value", PATH and HOME. PATH and HOME can't be
+// overridden by env. The plugin is killed if it doesn't complete before the
+// timeout
+func RunPlugin(path string, args, env []string, timeout time.Duration,
+	input *ReleaseContext) (verdict *PluginVerdict, err error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "grgate-plugin-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = workDir
+	cmd.Env = append(append([]string{}, env...), "PATH="+pluginPath, "HOME="+workDir)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+
+	if err = cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("plugin timed out after %s", timeout)
+		}
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			if len(output) > pluginStderrLimit {
+				output = output[:pluginStderrLimit] + "..."
+			}
+			return nil, fmt.Errorf("%w: %s", err, output)
+		}
+		return nil, err
+	}
+
+	if err = json.Unmarshal(stdout.Bytes(), &verdict); err != nil {
+		return nil, fmt.Errorf("couldn't decode plugin verdict: %w", err)
+	}
+
+	switch verdict.State {
+	case PluginStateSuccess, PluginStatePending, PluginStateFailure:
+		return verdict, nil
+	}
+
+	return nil, fmt.Errorf("unsupported plugin state \"%s\"", verdict.State)
+}
@@ -0,0 +1,105 @@
+//go:build unit
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func writePlugin(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "plugin")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("Error not expected: %#v", err)
+	}
+	return path
+}
+
+func TestRunPlugin(t *testing.T) {
+	input := &ReleaseContext{
+		Owner:      "fikaworks",
+		Repository: "grgate",
+		Release:    &ReleaseContextRelease{Tag: "v1.2.3"},
+	}
+
+	testCases := []struct {
+		name        string
+		script      string
+		env         []string
+		timeout     time.Duration
+		expected    *PluginVerdict
+		expectError bool
+	}{
+		{
+			name:     "should return the plugin verdict",
+			script:   `echo '{"state":"failure","message":"license GPL-3.0 not allowed"}'`,
+			expected: &PluginVerdict{State: "failure", Message: "license GPL-3.0 not allowed"},
+		},
+		{
+			name: "should pass the release context on stdin",
+			script: `grep -q '"tag":"v1.2.3"' && echo '{"state":"success"}' ||
+echo '{"state":"failure"}'`,
+			expected: &PluginVerdict{State: "success"},
+		},
+		{
+			name: "should only pass the provided environment",
+			script: `[ -z "$SECRET" ] && [ "$LEVEL" = "high" ] &&
+echo '{"state":"pending"}' || echo '{"state":"failure"}'`,
+			env:      []string{"LEVEL=high"},
+			expected: &PluginVerdict{State: "pending"},
+		},
+		{
+			name: "should not let the provided environment override PATH",
+			script: `[ "$PATH" = "/usr/local/bin:/usr/bin:/bin" ] &&
+echo '{"state":"success"}' || echo '{"state":"failure"}'`,
+			env:      []string{"PATH=/tmp"},
+			expected: &PluginVerdict{State: "success"},
+		},
+		{
+			name:        "should return an error if the plugin exit with an error",
+			script:      `echo "scan failed" >&2; exit 1`,
+			expectError: true,
+		},
+		{
+			name:        "should return an error if the verdict is not supported",
+			script:      `echo '{"state":"maybe"}'`,
+			expectError: true,
+		},
+		{
+			name:        "should return an error if the plugin timed out",
+			script:      `sleep 5`,
+			timeout:     100 * time.Millisecond,
+			expectError: true,
+		},
+	}
+
+	t.Setenv("SECRET", "secret")
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			timeout := testCase.timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+
+			result, err := RunPlugin(writePlugin(t, testCase.script), nil,
+				testCase.env, timeout, input)
+			if testCase.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %#v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+			if diff := pretty.Compare(result, testCase.expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+const (
+	// PolicyDecisionAllow allow the release to be published
+	PolicyDecisionAllow = "allow"
+
+	// PolicyDecisionDeny block the release
+	PolicyDecisionDeny = "deny"
+
+	// PolicyDecisionPending hold the release until the policy is satisfied
+	PolicyDecisionPending = "pending"
+
+	// policyCostLimit limit the cost of a policy evaluation so that a policy
+	// can't exhaust the server resources
+	policyCostLimit = 1000000
+)
+
+// PolicyInput hold the data exposed to policy expressions
+type PolicyInput struct {
+	Release     *platforms.Release
+	PreviousTag string
+	Statuses    []*platforms.Status
+	Commit      *platforms.Commit
+	Now         time.Time
+}
+
+// VersionBump returns the kind of bump between two versions, one of major,
+// minor, patch or prerelease. An empty string is returned if the previous
+// version is empty or if one of the versions is invalid
+func VersionBump(previous, current string) string {
+	if previous == "" {
+		return ""
+	}
+
+	vp, err := parseVersion(previous)
+	if err != nil {
+		return ""
+	}
+
+	vc, err := parseVersion(current)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case vc.numbers[0] != vp.numbers[0]:
+		return "major"
+	case vc.numbers[1] != vp.numbers[1]:
+		return "minor"
+	case vc.numbers[2] != vp.numbers[2]:
+		return "patch"
+	}
+
+	return "prerelease"
+}
+
+// variables returns the policy input as CEL variables
+func (i *PolicyInput) variables() map[string]interface{} {
+	release := map[string]interface{}{
+		"name":        i.Release.Name,
+		"tag":         i.Release.Tag,
+		"commitSha":   i.Release.CommitSha,
+		"createdAt":   i.Release.CreatedAt,
+		"previousTag": i.PreviousTag,
+		"bump":        VersionBump(i.PreviousTag, i.Release.Tag),
+	}
+
+	statuses := make(map[string]string)
+	for _, status := range i.Statuses {
+		state := status.State
+		if state == "" {
+			state = status.Status
+		}
+		statuses[status.Name] = state
+	}
+
+	commit := map[string]interface{}{
+		"sha":         "",
+		"message":     "",
+		"author":      "",
+		"authorEmail": "",
+		"date":        time.Time{},
+	}
+	if i.Commit != nil {
+		commit["sha"] = i.Commit.Sha
+		commit["message"] = i.Commit.Message
+		commit["author"] = i.Commit.Author
+		commit["authorEmail"] = i.Commit.AuthorEmail
+		commit["date"] = i.Commit.Date
+	}
+
+	return map[string]interface{}{
+		"release":  release,
+		"statuses": statuses,
+		"commit":   commit,
+		"now":      i.Now,
+	}
+}
+
+// newPolicyEnv returns the CEL environment policy expressions are compiled
+// against
+func newPolicyEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("release", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("statuses", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("commit", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("now", cel.TimestampType),
+	)
+}
+
+// EvaluatePolicy evaluate a CEL expression against the policy input and
+// returns its decision. The expression must return either a boolean, true
+// allowing the release and false holding it, or one of the allow, deny or
+// pending decisions
+func EvaluatePolicy(expression string, input *PolicyInput) (decision string, err error) {
+	env, err := newPolicyEnv()
+	if err != nil {
+		return "", err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return "", fmt.Errorf("invalid policy expression: %w", issues.Err())
+	}
+
+	program, err := env.Program(ast, cel.CostLimit(policyCostLimit))
+	if err != nil {
+		return "", err
+	}
+
+	out, _, err := program.Eval(input.variables())
+	if err != nil {
+		return "", fmt.Errorf("couldn't evaluate policy: %w", err)
+	}
+
+	switch value := out.Value().(type) {
+	case bool:
+		if value {
+			return PolicyDecisionAllow, nil
+		}
+		return PolicyDecisionPending, nil
+	case string:
+		switch value {
+		case PolicyDecisionAllow, PolicyDecisionDeny, PolicyDecisionPending:
+			return value, nil
+		}
+		return "", fmt.Errorf("unsupported policy decision \"%s\"", value)
+	}
+
+	return "", fmt.Errorf("policy must return a boolean or a decision, got %s",
+		out.Type().TypeName())
+}
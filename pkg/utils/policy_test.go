@@ -0,0 +1,110 @@
+//go:build unit
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+func TestVersionBump(t *testing.T) {
+	testCases := []struct {
+		previous string
+		current  string
+		expected string
+	}{
+		{previous: "v1.2.3", current: "v2.0.0", expected: "major"},
+		{previous: "v1.2.3", current: "v1.3.0", expected: "minor"},
+		{previous: "v1.2.3", current: "v1.2.4", expected: "patch"},
+		{previous: "v1.2.3-rc.1", current: "v1.2.3", expected: "prerelease"},
+		{previous: "", current: "v1.0.0", expected: ""},
+		{previous: "latest", current: "v1.0.0", expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.previous+" to "+testCase.current, func(t *testing.T) {
+			if result := VersionBump(testCase.previous, testCase.current); result != testCase.expected {
+				t.Errorf("Expected %s, got %s", testCase.expected, result)
+			}
+		})
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	input := &PolicyInput{
+		Release: &platforms.Release{
+			Tag:       "v2.0.0",
+			CreatedAt: now.Add(-2 * time.Hour),
+		},
+		PreviousTag: "v1.4.2",
+		Statuses: []*platforms.Status{
+			{Name: "e2e", Status: "completed", State: "success"},
+			{Name: "approval", Status: "pending"},
+		},
+		Commit: &platforms.Commit{
+			Message: "feat!: drop v1 API",
+			Author:  "Jane",
+		},
+		Now: now,
+	}
+
+	testCases := []struct {
+		name        string
+		expression  string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:       "should allow if the expression is true",
+			expression: `statuses["e2e"] == "success" && commit.author == "Jane"`,
+			expected:   PolicyDecisionAllow,
+		},
+		{
+			name:       "should hold if the expression is false",
+			expression: `release.bump != "major" || now - release.createdAt > duration("24h")`,
+			expected:   PolicyDecisionPending,
+		},
+		{
+			name:       "should return the decision of the expression",
+			expression: `commit.message.startsWith("feat!") && statuses["approval"] != "success" ? "deny" : "allow"`,
+			expected:   PolicyDecisionDeny,
+		},
+		{
+			name:        "should return an error if the expression is invalid",
+			expression:  `release.tag ==`,
+			expectError: true,
+		},
+		{
+			name:        "should return an error if the decision is not supported",
+			expression:  `"maybe"`,
+			expectError: true,
+		},
+		{
+			name:        "should return an error if the result is not a decision",
+			expression:  `42`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := EvaluatePolicy(testCase.expression, input)
+			if testCase.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %s", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+			if result != testCase.expected {
+				t.Errorf("Expected %s, got %s", testCase.expected, result)
+			}
+		})
+	}
+}
@@ -2,22 +2,44 @@ package utils
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/fikaworks/grgate/pkg/config"
 	"github.com/fikaworks/grgate/pkg/platforms"
 )
 
 var (
-	releaseNoteMarkerStart = config.DefaultReleaseNoteMarkerStart
-	releaseNoteMarkerEnd   = config.DefaultReleaseNoteMarkerEnd
+	releaseNoteMarkerStart   = config.DefaultReleaseNoteMarkerStart
+	releaseNoteMarkerEnd     = config.DefaultReleaseNoteMarkerEnd
+	rollbackMarkerStart      = config.DefaultRollbackMarkerStart
+	rollbackMarkerEnd        = config.DefaultRollbackMarkerEnd
+	timeoutMarkerStart       = config.DefaultTimeoutMarkerStart
+	timeoutMarkerEnd         = config.DefaultTimeoutMarkerEnd
+	retriesMarkerPrefix      = config.DefaultRetriesMarkerPrefix
+	triggersMarkerPrefix     = config.DefaultTriggersMarkerPrefix
+	timeoutStartMarkerPrefix = config.DefaultTimeoutStartMarkerPrefix
 )
 
+// hiddenCommentSuffix close the hidden comments used to keep track of GRGate
+// state in the release note
+const hiddenCommentSuffix = " -->"
+
+// rollbackNoticePrefix is the text preceding the reason in the rollback notice
+const rollbackNoticePrefix = "> Release reverted to draft by GRGate: "
+
+// timeoutNoticePrefix is the text preceding the reason in the timeout notice
+const timeoutNoticePrefix = "> Release blocked by GRGate: "
+
 // ReleaseNoteData hold release data used to populate the release note template
 type ReleaseNoteData struct {
+	Blockers    []*platforms.Issue
 	ReleaseNote string
+	Signature   *platforms.Signature
 	Statuses    []*platforms.Status
 }
 
@@ -68,3 +90,176 @@ func MergeStatuses(platformStatuses []*platforms.Status, configStatuses []string
 
 	return platformStatuses
 }
+
+// SetRollbackReason add/update the rollback notice of a release note. The
+// notice is inserted before the status check section so that it is kept when
+// the release note is rendered again
+func SetRollbackReason(releaseNote, reason string) string {
+	return setNotice(releaseNote, rollbackMarkerStart, rollbackMarkerEnd,
+		rollbackNoticePrefix, reason)
+}
+
+// GetRollbackReason returns the reason of the rollback notice attached to a
+// release note, empty if the release has not been rolled back
+func GetRollbackReason(releaseNote string) string {
+	return getNotice(releaseNote, rollbackMarkerStart, rollbackMarkerEnd,
+		rollbackNoticePrefix)
+}
+
+// SetTimeoutReason add/update the timeout notice of a release note. Like the
+// rollback notice, it is kept when the release note is rendered again
+func SetTimeoutReason(releaseNote, reason string) string {
+	return setNotice(releaseNote, timeoutMarkerStart, timeoutMarkerEnd,
+		timeoutNoticePrefix, reason)
+}
+
+// GetTimeoutReason returns the reason of the timeout notice attached to a
+// release note, empty if the release didn't exceed the timeout
+func GetTimeoutReason(releaseNote string) string {
+	return getNotice(releaseNote, timeoutMarkerStart, timeoutMarkerEnd,
+		timeoutNoticePrefix)
+}
+
+// setNotice add/update a warning notice delimited by markers in a release
+// note, the notice is inserted before the status check section
+func setNotice(releaseNote, markerStart, markerEnd, prefix, reason string) string {
+	start := strings.Index(releaseNote, markerStart)
+	end := strings.Index(releaseNote, markerEnd)
+	if start > -1 && end > -1 {
+		releaseNote = releaseNote[0:start] +
+			strings.TrimPrefix(releaseNote[end+len(markerEnd):], "\n")
+	}
+
+	notice := fmt.Sprintf("%s\n> **Warning**\n%s%s\n%s\n",
+		markerStart, prefix, reason, markerEnd)
+
+	statusStart := strings.Index(releaseNote, releaseNoteMarkerStart)
+	if statusStart > -1 {
+		return releaseNote[0:statusStart] + notice + releaseNote[statusStart:]
+	}
+
+	return strings.TrimRight(releaseNote, "\n") + "\n\n" + notice
+}
+
+// getNotice returns the reason of a notice delimited by markers, empty if the
+// notice is not found
+func getNotice(releaseNote, markerStart, markerEnd, prefix string) string {
+	start := strings.Index(releaseNote, markerStart)
+	end := strings.Index(releaseNote, markerEnd)
+	if start < 0 || end < start {
+		return ""
+	}
+
+	notice := releaseNote[start+len(markerStart) : end]
+	if i := strings.Index(notice, prefix); i > -1 {
+		return strings.TrimSpace(notice[i+len(prefix):])
+	}
+
+	return strings.TrimSpace(notice)
+}
+
+// FailedStatuses returns the name of the required statuses which failed
+func FailedStatuses(platformStatuses []*platforms.Status, configStatuses []string) (failed []string) {
+	for _, configStatus := range configStatuses {
+		for _, platformStatus := range platformStatuses {
+			if platformStatus.Name == configStatus && platformStatus.IsFailed() {
+				failed = append(failed, configStatus)
+				break
+			}
+		}
+	}
+	return
+}
+
+// GetRetryAttempts returns the number of times each status has been retried,
+// indexed by status name, as recorded in the release note
+func GetRetryAttempts(releaseNote string) map[string]int {
+	attempts := make(map[string]int)
+	if err := getHiddenComment(releaseNote, retriesMarkerPrefix, &attempts); err != nil {
+		return make(map[string]int)
+	}
+	return attempts
+}
+
+// SetRetryAttempts add/update the hidden comment recording retry attempts in
+// a release note
+func SetRetryAttempts(releaseNote string, attempts map[string]int) string {
+	return setHiddenComment(releaseNote, retriesMarkerPrefix, attempts)
+}
+
+// GetTriggeredWorkflows returns the name of the workflows already triggered
+// for a release, as recorded in the release note
+func GetTriggeredWorkflows(releaseNote string) (workflows []string) {
+	if err := getHiddenComment(releaseNote, triggersMarkerPrefix, &workflows); err != nil {
+		return nil
+	}
+	return
+}
+
+// SetTriggeredWorkflows add/update the hidden comment recording the workflows
+// triggered for a release in a release note
+func SetTriggeredWorkflows(releaseNote string, workflows []string) string {
+	return setHiddenComment(releaseNote, triggersMarkerPrefix, workflows)
+}
+
+// GetTimeoutStart returns when the timeout of a release started, as recorded
+// in the release note. The zero time is returned if it is not recorded
+func GetTimeoutStart(releaseNote string) (start time.Time) {
+	if err := getHiddenComment(releaseNote, timeoutStartMarkerPrefix, &start); err != nil {
+		return time.Time{}
+	}
+	return
+}
+
+// SetTimeoutStart add/update the hidden comment recording when the timeout of
+// a release started in a release note
+func SetTimeoutStart(releaseNote string, start time.Time) string {
+	return setHiddenComment(releaseNote, timeoutStartMarkerPrefix,
+		start.UTC().Truncate(time.Second))
+}
+
+// getHiddenComment decode the JSON value of the hidden comment starting with
+// prefix. The value is left untouched if the comment is not found
+func getHiddenComment(releaseNote, prefix string, value interface{}) error {
+	start := strings.Index(releaseNote, prefix)
+	if start < 0 {
+		return nil
+	}
+
+	content := releaseNote[start+len(prefix):]
+	end := strings.Index(content, hiddenCommentSuffix)
+	if end < 0 {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(content[:end]), value)
+}
+
+// setHiddenComment add/update a hidden comment holding a JSON value in a
+// release note. Like the rollback notice, the comment is inserted before the
+// status check section so that it is kept when the release note is rendered
+// again
+func setHiddenComment(releaseNote, prefix string, value interface{}) string {
+	start := strings.Index(releaseNote, prefix)
+	if start > -1 {
+		end := strings.Index(releaseNote[start:], hiddenCommentSuffix)
+		if end > -1 {
+			releaseNote = releaseNote[0:start] + strings.TrimPrefix(
+				releaseNote[start+end+len(hiddenCommentSuffix):], "\n")
+		}
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return releaseNote
+	}
+
+	comment := prefix + string(content) + hiddenCommentSuffix + "\n"
+
+	statusStart := strings.Index(releaseNote, releaseNoteMarkerStart)
+	if statusStart > -1 {
+		return releaseNote[0:statusStart] + comment + releaseNote[statusStart:]
+	}
+
+	return strings.TrimRight(releaseNote, "\n") + "\n\n" + comment
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+// ReleaseContext hold the release data sent to external decision services
+// and plugins
+type ReleaseContext struct {
+	Owner      string                  `json:"owner"`
+	Repository string                  `json:"repository"`
+	Release    *ReleaseContextRelease  `json:"release"`
+	Commit     *ReleaseContextCommit   `json:"commit,omitempty"`
+	Statuses   []*ReleaseContextStatus `json:"statuses"`
+}
+
+// ReleaseContextRelease hold the release of a release context
+type ReleaseContextRelease struct {
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	CommitSha string `json:"commitSha"`
+}
+
+// ReleaseContextCommit hold the release commit of a release context
+type ReleaseContextCommit struct {
+	Sha         string    `json:"sha"`
+	Message     string    `json:"message"`
+	Author      string    `json:"author"`
+	AuthorEmail string    `json:"authorEmail"`
+	Date        time.Time `json:"date"`
+}
+
+// NewReleaseContextCommit convert a platform commit to a release context
+// commit
+func NewReleaseContextCommit(commit *platforms.Commit) *ReleaseContextCommit {
+	return &ReleaseContextCommit{
+		Sha:         commit.Sha,
+		Message:     commit.Message,
+		Author:      commit.Author,
+		AuthorEmail: commit.AuthorEmail,
+		Date:        commit.Date,
+	}
+}
+
+// ReleaseContextStatus hold a commit status of a release context
+type ReleaseContextStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	State  string `json:"state,omitempty"`
+}
+
+// NewReleaseContextStatuses convert platform statuses to release context
+// statuses
+func NewReleaseContextStatuses(statusList []*platforms.Status) (statuses []*ReleaseContextStatus) {
+	statuses = make([]*ReleaseContextStatus, 0, len(statusList))
+	for _, status := range statusList {
+		statuses = append(statuses, &ReleaseContextStatus{
+			Name:   status.Name,
+			Status: status.Status,
+			State:  status.State,
+		})
+	}
+	return
+}
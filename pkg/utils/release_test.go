@@ -4,6 +4,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/fikaworks/grgate/pkg/platforms"
 	"github.com/kylelemons/godebug/pretty"
@@ -137,3 +138,161 @@ func TestMergeStatuses(t *testing.T) {
 		t.Errorf("diff: (-got +want)\n%s", diff)
 	}
 }
+
+func TestSetRollbackReason(t *testing.T) {
+	releaseNote := `This is a release note
+<!-- GRGate start -->
+<details><summary>Status check</summary>
+
+- [x] e2e A
+
+</details>
+<!-- GRGate end -->`
+
+	expected := `This is a release note
+<!-- GRGate rollback start -->
+> **Warning**
+> Release reverted to draft by GRGate: e2e A failed
+<!-- GRGate rollback end -->
+<!-- GRGate start -->
+<details><summary>Status check</summary>
+
+- [x] e2e A
+
+</details>
+<!-- GRGate end -->`
+
+	result := SetRollbackReason(releaseNote, "e2e A failed")
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	// setting the reason again should replace the existing notice
+	result = SetRollbackReason(result, "e2e A failed")
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	if reason := GetRollbackReason(result); reason != "e2e A failed" {
+		t.Errorf("Expected reason %q, got %q", "e2e A failed", reason)
+	}
+
+	if reason := GetRollbackReason(releaseNote); reason != "" {
+		t.Errorf("Expected empty reason, got %q", reason)
+	}
+}
+
+func TestSetTimeoutReason(t *testing.T) {
+	expected := `This is a release note
+
+<!-- GRGate timeout start -->
+> **Warning**
+> Release blocked by GRGate: required statuses didn't succeed within 72h0m0s
+<!-- GRGate timeout end -->
+`
+
+	result := SetTimeoutReason("This is a release note",
+		"required statuses didn't succeed within 72h0m0s")
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	if reason := GetTimeoutReason(result); reason !=
+		"required statuses didn't succeed within 72h0m0s" {
+		t.Errorf("Unexpected reason %q", reason)
+	}
+
+	if reason := GetRollbackReason(result); reason != "" {
+		t.Errorf("Expected empty rollback reason, got %q", reason)
+	}
+}
+
+func TestSetRetryAttempts(t *testing.T) {
+	releaseNote := `This is a release note
+<!-- GRGate start -->
+<details><summary>Status check</summary>
+
+- [ ] e2e A
+
+</details>
+<!-- GRGate end -->`
+
+	expected := `This is a release note
+<!-- GRGate retries: {"e2e A":2} -->
+<!-- GRGate start -->
+<details><summary>Status check</summary>
+
+- [ ] e2e A
+
+</details>
+<!-- GRGate end -->`
+
+	result := SetRetryAttempts(releaseNote, map[string]int{"e2e A": 1})
+
+	attempts := GetRetryAttempts(result)
+	attempts["e2e A"]++
+
+	// setting the attempts again should replace the existing comment
+	result = SetRetryAttempts(result, attempts)
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	if attempts := GetRetryAttempts(releaseNote); len(attempts) != 0 {
+		t.Errorf("Expected no attempts, got %v", attempts)
+	}
+}
+
+func TestSetTimeoutStart(t *testing.T) {
+	start := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	expected := `This is a release note
+
+<!-- GRGate timeout started: "2023-06-01T12:00:00Z" -->
+`
+
+	result := SetTimeoutStart("This is a release note", start.Add(time.Millisecond))
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	if result := GetTimeoutStart(result); !result.Equal(start) {
+		t.Errorf("Expected %s, got %s", start, result)
+	}
+
+	if result := GetTimeoutStart("This is a release note"); !result.IsZero() {
+		t.Errorf("Expected zero time, got %s", result)
+	}
+}
+
+func TestFailedStatuses(t *testing.T) {
+	expected := []string{"e2e B", "e2e C"}
+	result := FailedStatuses([]*platforms.Status{
+		{
+			Name:   "e2e A",
+			Status: "completed",
+			State:  "success",
+		},
+		{
+			Name:   "e2e B",
+			Status: "completed",
+			State:  "failure",
+		},
+		{
+			Name:   "e2e C",
+			Status: "failed",
+		},
+		{
+			Name:   "e2e D",
+			Status: "failed",
+		},
+	}, []string{
+		"e2e A",
+		"e2e B",
+		"e2e C",
+	})
+
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+}
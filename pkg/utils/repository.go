@@ -2,8 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
+
+	"github.com/fikaworks/grgate/pkg/config"
 )
 
 type Repository struct {
@@ -79,3 +82,36 @@ func ExtractRepository(input string) (repository *Repository, err error) {
 
 	return
 }
+
+// MatchRepository returns true if the repository match the glob pattern.
+// Patterns are matched case insensitively against "owner/name", a pattern
+// ending with "/**" match all the repositories of an owner or Gitlab group and
+// its subgroups. Patterns prefixed by "topic:" are matched against the
+// repository topics
+func MatchRepository(pattern, owner, name string, topics []string) bool {
+	pattern = strings.ToLower(pattern)
+
+	if topicPattern, ok := strings.CutPrefix(pattern, config.RepositoryTopicPrefix); ok {
+		for _, topic := range topics {
+			if match, _ := path.Match(topicPattern, strings.ToLower(topic)); match {
+				return true
+			}
+		}
+		return false
+	}
+
+	owner = strings.ToLower(owner)
+
+	if groupPattern, ok := strings.CutSuffix(pattern, "/**"); ok {
+		groups := strings.Split(owner, "/")
+		for i := range groups {
+			if match, _ := path.Match(groupPattern, strings.Join(groups[:i+1], "/")); match {
+				return true
+			}
+		}
+		return false
+	}
+
+	match, _ := path.Match(pattern, owner+"/"+strings.ToLower(name))
+	return match
+}
@@ -169,3 +169,32 @@ func TestExtractRepository(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchRepository(t *testing.T) {
+	topics := []string{"backend", "Release-Gated"}
+
+	testCases := []struct {
+		pattern  string
+		owner    string
+		expected bool
+	}{
+		{"fikaworks/grgate", "fikaworks", true},
+		{"FikaWorks/*", "fikaworks", true},
+		{"fikaworks/grgate-*", "fikaworks", false},
+		{"other/*", "fikaworks", false},
+		{"group/*", "group/subgroup", false},
+		{"group/subgroup/*", "group/subgroup", true},
+		{"group/**", "group/subgroup", true},
+		{"group/**", "group", true},
+		{"grou/**", "group", false},
+		{"topic:release-gated", "fikaworks", true},
+		{"topic:front*", "fikaworks", false},
+	}
+	for _, testCase := range testCases {
+		if result := MatchRepository(testCase.pattern, testCase.owner, "grgate",
+			topics); result != testCase.expected {
+			t.Errorf("Pattern %s with owner %s, got %t, expected %t",
+				testCase.pattern, testCase.owner, result, testCase.expected)
+		}
+	}
+}
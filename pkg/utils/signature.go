@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // detached signature verification only
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshSignatureMagic is the preamble of SSH signatures
+	sshSignatureMagic = "SSHSIG"
+
+	// sshSignatureNamespace is the namespace used by Git to sign objects
+	sshSignatureNamespace = "git"
+
+	// sshSignaturePEMType is the PEM block type of armored SSH signatures
+	sshSignaturePEMType = "SSH SIGNATURE"
+)
+
+// sshSignature represent the blob of an armored SSH signature
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData represent the data signed when creating an SSH signature
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// IsSSHSignature returns true if the armored signature is an SSH signature
+func IsSSHSignature(signature string) bool {
+	return strings.Contains(signature, "-----BEGIN "+sshSignaturePEMType+"-----")
+}
+
+// VerifyGPGSignature verify an armored detached GPG signature of a payload
+// against a keyring and returns the fingerprint of the key which signed it
+func VerifyGPGSignature(keyring io.Reader, payload, signature string) (fingerprint string, err error) {
+	entities, err := openpgp.ReadArmoredKeyRing(keyring)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read keyring: %w", err)
+	}
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(entities,
+		strings.NewReader(payload), strings.NewReader(signature))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint), nil
+}
+
+// VerifySSHSignature verify an armored SSH signature of a payload against an
+// allowed signers file (see ssh-keygen(1)) and returns the principals of the
+// key which signed it
+func VerifySSHSignature(allowedSigners io.Reader, payload, signature string) (principals string, err error) {
+	block, _ := pem.Decode([]byte(signature))
+	if block == nil || block.Type != sshSignaturePEMType {
+		return "", errors.New("invalid SSH signature")
+	}
+
+	if !bytes.HasPrefix(block.Bytes, []byte(sshSignatureMagic)) {
+		return "", errors.New("invalid SSH signature preamble")
+	}
+
+	var sig sshSignature
+	if err = ssh.Unmarshal(block.Bytes[len(sshSignatureMagic):], &sig); err != nil {
+		return "", fmt.Errorf("couldn't parse SSH signature: %w", err)
+	}
+
+	if sig.Namespace != sshSignatureNamespace {
+		return "", fmt.Errorf("unexpected SSH signature namespace %s", sig.Namespace)
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported SSH signature hash algorithm %s",
+			sig.HashAlgorithm)
+	}
+	h.Write([]byte(payload))
+
+	publicKey, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("couldn't parse SSH signature public key: %w", err)
+	}
+
+	var sshSig ssh.Signature
+	if err = ssh.Unmarshal(sig.Signature, &sshSig); err != nil {
+		return "", fmt.Errorf("couldn't parse SSH signature: %w", err)
+	}
+
+	signedData := append([]byte(sshSignatureMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+
+	if err = publicKey.Verify(signedData, &sshSig); err != nil {
+		return "", err
+	}
+
+	return findAllowedSigner(allowedSigners, publicKey)
+}
+
+// findAllowedSigner returns the principals associated to a public key in an
+// allowed signers file
+func findAllowedSigner(allowedSigners io.Reader, publicKey ssh.PublicKey) (string, error) {
+	scanner := bufio.NewScanner(allowedSigners)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// each line is formatted as: principals [options] keytype key [comment]
+		fields := strings.Fields(line)
+		for i := 1; i < len(fields) && i <= 2; i++ {
+			key, _, _, _, err := ssh.ParseAuthorizedKey(
+				[]byte(strings.Join(fields[i:], " ")))
+			if err != nil {
+				continue
+			}
+			if bytes.Equal(key.Marshal(), publicKey.Marshal()) {
+				return fields[0], nil
+			}
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", errors.New("signing key is not an allowed signer")
+}
@@ -0,0 +1,137 @@
+//go:build unit
+
+package utils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"       //nolint:staticcheck // used to sign test payloads
+	"golang.org/x/crypto/openpgp/armor" //nolint:staticcheck // used to export test keys
+	"golang.org/x/crypto/ssh"
+)
+
+const testPayload = `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author GRGate <grgate@example.com> 1700000000 +0000
+committer GRGate <grgate@example.com> 1700000000 +0000
+
+initial commit
+`
+
+// signSSH create an armored SSH signature of the payload
+func signSSH(t *testing.T, signer ssh.Signer, payload string) string {
+	h := sha512.Sum512([]byte(payload))
+	signedData := append([]byte(sshSignatureMagic), ssh.Marshal(sshSignedData{
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          h[:],
+	})...)
+
+	sig, err := signer.Sign(rand.Reader, signedData)
+	if err != nil {
+		t.Fatalf("Error signing payload: %#v", err)
+	}
+
+	blob := append([]byte(sshSignatureMagic), ssh.Marshal(sshSignature{
+		Version:       1,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(sig),
+	})...)
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  sshSignaturePEMType,
+		Bytes: blob,
+	}))
+}
+
+func TestVerifySSHSignature(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %#v", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("Error creating signer: %#v", err)
+	}
+
+	signature := signSSH(t, signer, testPayload)
+	allowedSigners := "grgate@example.com " +
+		strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+
+	if !IsSSHSignature(signature) {
+		t.Errorf("Expected signature to be detected as SSH signature")
+	}
+
+	t.Run("should return principals of allowed signer", func(t *testing.T) {
+		principals, err := VerifySSHSignature(strings.NewReader(allowedSigners),
+			testPayload, signature)
+		if err != nil {
+			t.Errorf("Error not expected: %#v", err)
+		}
+		if principals != "grgate@example.com" {
+			t.Errorf("Expected principals grgate@example.com, got %s", principals)
+		}
+	})
+
+	t.Run("should fail if payload has been tampered", func(t *testing.T) {
+		_, err := VerifySSHSignature(strings.NewReader(allowedSigners),
+			testPayload+"tampered", signature)
+		if err == nil {
+			t.Errorf("Expected error with tampered payload")
+		}
+	})
+
+	t.Run("should fail if signer is not allowed", func(t *testing.T) {
+		_, err := VerifySSHSignature(strings.NewReader(""), testPayload, signature)
+		if err == nil {
+			t.Errorf("Expected error with signer not allowed")
+		}
+	})
+}
+
+func TestVerifyGPGSignature(t *testing.T) {
+	entity, err := openpgp.NewEntity("GRGate", "", "grgate@example.com", nil)
+	if err != nil {
+		t.Fatalf("Error generating key: %#v", err)
+	}
+
+	var signature bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&signature, entity,
+		strings.NewReader(testPayload), nil); err != nil {
+		t.Fatalf("Error signing payload: %#v", err)
+	}
+
+	var keyring bytes.Buffer
+	writer, err := armor.Encode(&keyring, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("Error armoring key: %#v", err)
+	}
+	if err := entity.Serialize(writer); err != nil {
+		t.Fatalf("Error serializing key: %#v", err)
+	}
+	writer.Close()
+
+	fingerprint, err := VerifyGPGSignature(bytes.NewReader(keyring.Bytes()),
+		testPayload, signature.String())
+	if err != nil {
+		t.Errorf("Error not expected: %#v", err)
+	}
+	expected := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+	if fingerprint != expected {
+		t.Errorf("Expected fingerprint %s, got %s", expected, fingerprint)
+	}
+
+	if _, err := VerifyGPGSignature(bytes.NewReader(keyring.Bytes()),
+		testPayload+"tampered", signature.String()); err == nil {
+		t.Errorf("Expected error with tampered payload")
+	}
+}
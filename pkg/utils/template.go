@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// TemplateData hold release data used to render values defined in config,
+// ie: workflow trigger inputs or metric queries
+type TemplateData struct {
+	CommitSha  string
+	Name       string
+	Owner      string
+	Repository string
+	Tag        string
+}
+
+// RenderTemplate render a value defined in config based on a template
+func RenderTemplate(tpl string, data *TemplateData) (output string, err error) {
+	t, err := template.New("tpl").Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, data)
+	output = b.String()
+	return
+}
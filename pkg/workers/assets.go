@@ -0,0 +1,150 @@
+package workers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// assetsGateName is the name of the assets gate displayed in the release note
+const assetsGateName = "grgate/assets"
+
+// checkAssets make sure that all the required assets are attached to the
+// release and, if a checksum file is defined, that each of its entries match
+// the uploaded assets
+func (j *Job) checkAssets(release *platforms.Release) (result *gateResult, err error) {
+	result = &gateResult{
+		name:  assetsGateName,
+		state: gateSucceeded,
+	}
+
+	assetList, err := j.Platform.ListReleaseAssets(j.Owner, j.Repository, release)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't list release assets")
+		return nil, err
+	}
+
+	assets := make(map[string]*platforms.ReleaseAsset, len(assetList))
+	assetNames := make([]string, 0, len(assetList))
+	for _, asset := range assetList {
+		assets[asset.Name] = asset
+		assetNames = append(assetNames, asset.Name)
+	}
+
+	missing, err := utils.MissingAssets(j.Config.Assets.Required, assetNames)
+	if err != nil {
+		result.state = gateFailed
+		result.messages = append(result.messages, err.Error())
+		return result, nil
+	}
+
+	for _, pattern := range missing {
+		result.messages = append(result.messages,
+			fmt.Sprintf("missing asset matching \"%s\"", pattern))
+	}
+
+	if j.Config.Assets.ChecksumFile != "" {
+		messages, err := j.verifyChecksums(assets)
+		if err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msg("Couldn't verify release assets checksum")
+			return nil, err
+		}
+		result.messages = append(result.messages, messages...)
+	}
+
+	if len(result.messages) > 0 {
+		result.state = gateFailed
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Assets gate: %s", result.state)
+
+	return result, nil
+}
+
+// verifyChecksums download the checksum file and each asset it reference,
+// then returns a message for each missing or mismatching asset
+func (j *Job) verifyChecksums(assets map[string]*platforms.ReleaseAsset) (messages []string, err error) {
+	checksumAsset, ok := assets[j.Config.Assets.ChecksumFile]
+	if !ok {
+		messages = append(messages, fmt.Sprintf("missing checksum file \"%s\"",
+			j.Config.Assets.ChecksumFile))
+		return
+	}
+
+	content, err := j.Platform.DownloadReleaseAsset(j.Owner, j.Repository,
+		checksumAsset)
+	if err != nil {
+		return
+	}
+	defer content.Close()
+
+	checksums, err := utils.ParseChecksums(content)
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("invalid checksum file \"%s\": %s",
+			j.Config.Assets.ChecksumFile, err))
+		return messages, nil
+	}
+
+	names := make([]string, 0, len(checksums))
+	for name := range checksums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		checksum := checksums[name]
+		asset, ok := assets[name]
+		if !ok {
+			messages = append(messages, fmt.Sprintf(
+				"asset \"%s\" listed in checksum file is missing", name))
+			continue
+		}
+
+		valid, err := j.verifyAssetChecksum(asset, checksum)
+		if err != nil {
+			messages = append(messages, fmt.Sprintf(
+				"couldn't verify checksum of asset \"%s\": %s", name, err))
+			continue
+		}
+
+		if !valid {
+			messages = append(messages, fmt.Sprintf(
+				"checksum of asset \"%s\" doesn't match", name))
+		}
+	}
+
+	return messages, nil
+}
+
+// verifyAssetChecksum download an asset and compare its checksum
+func (j *Job) verifyAssetChecksum(asset *platforms.ReleaseAsset, checksum string) (bool, error) {
+	content, err := j.Platform.DownloadReleaseAsset(j.Owner, j.Repository, asset)
+	if err != nil {
+		return false, err
+	}
+	defer content.Close()
+
+	return utils.VerifyChecksum(content, checksum)
+}
@@ -0,0 +1,80 @@
+//go:build unit
+
+package workers
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestCheckAssets(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should block release with missing and mismatching assets",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ListReleaseAssets(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, _ *platforms.Release) ([]*platforms.ReleaseAsset, error) {
+					return []*platforms.ReleaseAsset{
+						{ID: int64(1), Name: "checksums.txt"},
+						{ID: int64(2), Name: "grgate_linux_amd64.tar.gz"},
+					}, nil
+				})
+
+			files := map[string]string{
+				"checksums.txt": `9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08  grgate_linux_amd64.tar.gz
+60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752  grgate_darwin_arm64.tar.gz`,
+				"grgate_linux_amd64.tar.gz": "tampered",
+			}
+
+			mockPlatforms.EXPECT().DownloadReleaseAsset(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, asset *platforms.ReleaseAsset) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader(files[asset.Name])), nil
+				}).Times(2)
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Assets: &config.Assets{
+						Enabled:      true,
+						Required:     []string{"*_linux_amd64.tar.gz", "*_windows_amd64.zip"},
+						ChecksumFile: "checksums.txt",
+					},
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			result, err := job.checkAssets(&platforms.Release{Tag: "v1.2.3"})
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			expected := []string{
+				"missing asset matching \"*_windows_amd64.zip\"",
+				"asset \"grgate_darwin_arm64.tar.gz\" listed in checksum file is missing",
+				"checksum of asset \"grgate_linux_amd64.tar.gz\" doesn't match",
+			}
+
+			if result.succeeded() {
+				t.Errorf("Expected assets gate to fail")
+			}
+			if diff := pretty.Compare(result.messages, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
@@ -0,0 +1,74 @@
+package workers
+
+import (
+	"fmt"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+// blockersGateName is the name of the blockers gate displayed in the release
+// note
+const blockersGateName = "grgate/blockers"
+
+// checkBlockers make sure that no open issue labeled as blocker, or attached
+// to the milestone named after the release tag, exist
+func (j *Job) checkBlockers(release *platforms.Release) (result *gateResult, err error) {
+	result = &gateResult{
+		name:  blockersGateName,
+		state: gateSucceeded,
+	}
+
+	var queries []*platforms.IssueQuery
+	for _, label := range j.Config.Blockers.Labels {
+		queries = append(queries, &platforms.IssueQuery{
+			Label:        label,
+			PullRequests: j.Config.Blockers.PullRequests,
+		})
+	}
+	if j.Config.Blockers.Milestone {
+		queries = append(queries, &platforms.IssueQuery{
+			Milestone:    release.Tag,
+			PullRequests: j.Config.Blockers.PullRequests,
+		})
+	}
+
+	found := make(map[string]bool)
+	for _, query := range queries {
+		issueList, err := j.Platform.SearchIssues(j.Owner, j.Repository, query)
+		if err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msg("Couldn't search blocker issues")
+			return nil, err
+		}
+
+		for _, issue := range issueList {
+			if found[issue.URL] {
+				continue
+			}
+			found[issue.URL] = true
+			result.issues = append(result.issues, issue)
+			result.messages = append(result.messages,
+				fmt.Sprintf("blocked by %s", issue.Title))
+		}
+	}
+
+	if len(result.issues) > 0 {
+		result.state = gateFailed
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Blockers gate: %s", result.state)
+
+	return result, nil
+}
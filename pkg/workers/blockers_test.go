@@ -0,0 +1,72 @@
+//go:build unit
+
+package workers
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestCheckBlockers(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should block release while blocker issues are open",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			issue := &platforms.Issue{
+				ID:    12,
+				Title: "Login is broken",
+				URL:   "https://github.com/owner/repository/issues/12",
+			}
+
+			mockPlatforms.EXPECT().SearchIssues(gomock.Any(), gomock.Any(),
+				&platforms.IssueQuery{Label: "release-blocker"}).
+				Return([]*platforms.Issue{issue}, nil)
+
+			mockPlatforms.EXPECT().SearchIssues(gomock.Any(), gomock.Any(),
+				&platforms.IssueQuery{Milestone: "v1.2.3"}).
+				Return([]*platforms.Issue{issue}, nil)
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Blockers: &config.Blockers{
+						Enabled:   true,
+						Labels:    []string{"release-blocker"},
+						Milestone: true,
+					},
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			result, err := job.checkBlockers(&platforms.Release{Tag: "v1.2.3"})
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			if result.succeeded() {
+				t.Errorf("Expected blockers gate to fail")
+			}
+
+			if diff := pretty.Compare(result.issues,
+				[]*platforms.Issue{issue}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			expected := []string{"blocked by Login is broken"}
+			if diff := pretty.Compare(result.messages, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
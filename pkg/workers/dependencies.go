@@ -0,0 +1,106 @@
+package workers
+
+import (
+	"fmt"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// dependenciesGateName is the name of the dependencies gate displayed in the
+// release note
+const dependenciesGateName = "grgate/dependencies"
+
+// registerDependent record that the job repository depends on the upstream
+// repository
+func (j *Job) registerDependent(dependency *utils.Dependency) {
+	upstream := dependency.Owner + "/" + dependency.Repository
+
+	dependents := &j.engine.dependents
+	dependents.Lock()
+	defer dependents.Unlock()
+
+	if _, ok := dependents.repositories[upstream]; !ok {
+		dependents.repositories[upstream] = make(map[string]struct{})
+	}
+	dependents.repositories[upstream][j.Owner+"/"+j.Repository] = struct{}{}
+}
+
+// checkDependencies make sure that each upstream repository defined in the
+// dependsOn config has a release matching the version constraint. The gate
+// stay pending until all the dependencies are satisfied, it is checked again
+// after dependsOnRecheckInterval
+func (j *Job) checkDependencies(release *platforms.Release) (result *gateResult, err error) {
+	result = &gateResult{
+		name:  dependenciesGateName,
+		state: gateSucceeded,
+	}
+
+	for _, input := range j.Config.DependsOn {
+		dependency, err := utils.ParseDependency(input, j.Owner)
+		if err != nil {
+			result.state = gateFailed
+			result.messages = append(result.messages, err.Error())
+			continue
+		}
+
+		j.registerDependent(dependency)
+
+		satisfied, err := j.isDependencySatisfied(dependency)
+		if err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("Couldn't list releases of dependency %s/%s",
+					dependency.Owner, dependency.Repository)
+			return nil, err
+		}
+
+		if !satisfied {
+			if result.state == gateSucceeded {
+				result.state = gatePending
+			}
+			result.messages = append(result.messages,
+				fmt.Sprintf("waiting for %s", dependency))
+		}
+	}
+
+	if result.state == gatePending {
+		result.recheckAfter = j.Config.DependsOnRecheckInterval
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Dependencies gate: %s", result.state)
+
+	return result, nil
+}
+
+// isDependencySatisfied returns true if the upstream repository has a release
+// matching the version constraint and the expected state of the dependency
+func (j *Job) isDependencySatisfied(dependency *utils.Dependency) (bool, error) {
+	releaseList, err := j.Platform.ListReleases(dependency.Owner,
+		dependency.Repository)
+	if err != nil {
+		return false, err
+	}
+
+	for _, release := range releaseList {
+		if release.Draft && dependency.State == utils.DependencyStatePublished {
+			continue
+		}
+		if dependency.IsSatisfiedBy(release.Tag) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
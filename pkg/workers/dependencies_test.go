@@ -0,0 +1,81 @@
+//go:build unit
+
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should hold release until upstream release is published",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ListReleases("owner", "backend").DoAndReturn(
+				func(_ string, _ string) ([]*platforms.Release, error) {
+					return []*platforms.Release{
+						{Tag: "v2.3.0", Draft: true},
+						{Tag: "v2.2.0"},
+					}, nil
+				})
+
+			mockPlatforms.EXPECT().ListReleases("fikaworks", "api").DoAndReturn(
+				func(_ string, _ string) ([]*platforms.Release, error) {
+					return []*platforms.Release{
+						{Tag: "v1.0.0", Draft: true},
+					}, nil
+				})
+
+			job := &Job{
+				Platform:   mockPlatforms,
+				Owner:      "owner",
+				Repository: "frontend",
+				Config: &config.RepoConfig{
+					DependsOn: []string{
+						"backend >= v2.3.0 published",
+						"fikaworks/api >= v1.0.0 draft",
+					},
+					DependsOnRecheckInterval: 5 * time.Minute,
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			result, err := job.checkDependencies(&platforms.Release{Tag: "v1.2.3"})
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			if result.state != gatePending {
+				t.Errorf("Expected dependencies gate to be pending, got %s", result.state)
+			}
+
+			if result.recheckAfter != 5*time.Minute {
+				t.Errorf("Expected dependencies gate to be rechecked after 5m, got %s",
+					result.recheckAfter)
+			}
+
+			expected := []string{"waiting for owner/backend >= v2.3.0 published"}
+			if diff := pretty.Compare(result.messages, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			if diff := pretty.Compare(job.engine.Dependents("owner", "backend"),
+				[]string{"owner/frontend"}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
@@ -0,0 +1,206 @@
+package workers
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+// Options define the dependencies of an Engine
+type Options struct {
+	// Platform the releases are gated on
+	Platform platforms.Platform
+
+	// Config is the main config, it provides the global defaults of the
+	// repository configs
+	Config *config.MainConfig
+
+	// Logger used by the engine and its jobs, if nil the global logger is used
+	Logger *zerolog.Logger
+
+	// Registerer the engine metrics are registered with, if nil the metrics
+	// are not registered
+	Registerer prometheus.Registerer
+}
+
+// Engine gate the releases of repositories with a main config. Each engine
+// holds its own state so that several configurations can run in the same
+// process: the repository config cache, the dependents of each repository and
+// the publishes counted toward quotas
+type Engine struct {
+	mu       sync.RWMutex
+	platform platforms.Platform
+	loader   *config.Loader
+	logger   zerolog.Logger
+
+	// dependents keep track of the repositories depending on an upstream
+	// repository, indexed by "owner/repository" of the upstream repository.
+	// It is populated each time a job evaluate its dependencies, it is kept in
+	// memory so pending dependencies are also checked periodically
+	dependents struct {
+		sync.Mutex
+		repositories map[string]map[string]struct{}
+	}
+
+	// publishes keep track of the time releases were published, indexed by
+	// "owner/repository" for repository quotas and by owner for organization
+	// quotas. Counters are shared by the jobs of the engine and kept in memory,
+	// they are seeded from the releases of a repository the first time it is
+	// processed, seeded and seedRetry are indexed by "owner/repository".
+	// seedRetry holds the time after which a failed seed is attempted again
+	publishes struct {
+		sync.Mutex
+		times     map[string][]time.Time
+		seeded    map[string]struct{}
+		seedRetry map[string]time.Time
+	}
+
+	// skippedRepositories count the events of repositories which are not
+	// processed because of the repositories include/exclude lists
+	skippedRepositories *prometheus.CounterVec
+}
+
+// NewEngine return an Engine built from the provided options
+func NewEngine(opts Options) (*Engine, error) {
+	if opts.Platform == nil {
+		return nil, errors.New("platform is required")
+	}
+	if opts.Config == nil {
+		return nil, errors.New("config is required")
+	}
+
+	logger := log.Logger
+	if opts.Logger != nil {
+		logger = *opts.Logger
+	}
+
+	engine := newEngine(opts.Platform, opts.Config, logger)
+
+	if opts.Registerer != nil {
+		if err := opts.Registerer.Register(engine.skippedRepositories); err != nil {
+			return nil, err
+		}
+	}
+
+	return engine, nil
+}
+
+// newEngine returns an Engine without validating its dependencies
+func newEngine(platform platforms.Platform, mainConfig *config.MainConfig,
+	logger zerolog.Logger) *Engine {
+	engine := &Engine{
+		platform: platform,
+		loader:   config.NewLoader(mainConfig, logger),
+		logger:   logger,
+		skippedRepositories: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grgate_skipped_repositories_total",
+			Help: "Number of events skipped because the repository is not included or is excluded",
+		}, []string{"reason"}),
+	}
+	engine.dependents.repositories = make(map[string]map[string]struct{})
+	engine.publishes.times = make(map[string][]time.Time)
+	engine.publishes.seeded = make(map[string]struct{})
+	engine.publishes.seedRetry = make(map[string]time.Time)
+	return engine
+}
+
+// Update replace the platform and the main config of the engine, used when
+// the config is reloaded. The repository config cache is emptied, the
+// dependents and quota counters are kept
+func (e *Engine) Update(platform platforms.Platform, mainConfig *config.MainConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.platform = platform
+	e.loader = config.NewLoader(mainConfig, e.logger)
+}
+
+// Platform returns the platform of the engine
+func (e *Engine) Platform() platforms.Platform {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.platform
+}
+
+// Config returns the main config of the engine
+func (e *Engine) Config() *config.MainConfig {
+	return e.Loader().Config()
+}
+
+// Loader returns the repository config loader of the engine
+func (e *Engine) Loader() *config.Loader {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.loader
+}
+
+// Logger returns the logger of the engine
+func (e *Engine) Logger() *zerolog.Logger {
+	return &e.logger
+}
+
+// NewJob return a Job to be processed by a worker
+func (e *Engine) NewJob(owner, repository string) (job *Job, err error) {
+	e.mu.RLock()
+	platform, loader := e.platform, e.loader
+	e.mu.RUnlock()
+
+	repoConfig, err := loader.RepoConfig(platform, owner, repository)
+	if err != nil {
+		return
+	}
+
+	job = &Job{
+		Platform:   platform,
+		Owner:      owner,
+		Repository: repository,
+		Config:     repoConfig,
+		engine:     e,
+		main:       loader.Config(),
+	}
+	return
+}
+
+// Dependents returns the repositories, formatted as "owner/repository", which
+// depend on the provided repository. Only repositories processed at least once
+// by the engine are known
+func (e *Engine) Dependents(owner, repository string) (result []string) {
+	e.dependents.Lock()
+	defer e.dependents.Unlock()
+
+	for dependent := range e.dependents.repositories[owner+"/"+repository] {
+		result = append(result, dependent)
+	}
+	sort.Strings(result)
+	return
+}
+
+// InvalidateRepoConfigCache remove the cached config files changed by a push
+// to branch. Config files are read from the default branch or from the ref
+// defined by repoConfigRef, all the files of the repository are removed if
+// paths is nil
+func (e *Engine) InvalidateRepoConfigCache(owner, repository, branch,
+	defaultBranch string, paths []string) {
+	loader := e.Loader()
+
+	var refs []string
+	if branch == defaultBranch {
+		refs = append(refs, "")
+	}
+	if mainConfig := loader.Config(); mainConfig.RepoConfigMode == config.RepoConfigModeRef &&
+		branch == mainConfig.RepoConfigRef {
+		refs = append(refs, branch)
+	}
+
+	for _, ref := range refs {
+		loader.InvalidateRepoConfigCache(owner, repository, ref, paths...)
+	}
+}
@@ -0,0 +1,152 @@
+//go:build unit
+
+package workers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// newTestEngine returns an engine with an empty main config to run the jobs
+// of the tests
+func newTestEngine(platform platforms.Platform) *Engine {
+	return newEngine(platform, &config.MainConfig{}, zerolog.Nop())
+}
+
+func TestNewEngine(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should require a platform and a config", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		if _, err := NewEngine(Options{Config: &config.MainConfig{}}); err == nil {
+			t.Errorf("Expected missing platform to return an error")
+		}
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		if _, err := NewEngine(Options{Platform: mockPlatforms}); err == nil {
+			t.Errorf("Expected missing config to return an error")
+		}
+	})
+
+	t.Run("should register the metrics with the registerer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		registry := prometheus.NewRegistry()
+
+		for i := 0; i < 2; i++ {
+			_, err := NewEngine(Options{
+				Platform:   mockPlatforms,
+				Config:     &config.MainConfig{},
+				Registerer: registry,
+			})
+			if i == 0 && err != nil {
+				t.Errorf("Error not expected: %#v", err)
+			}
+			if i == 1 && err == nil {
+				t.Errorf("Expected registering the metrics twice to return an error")
+			}
+		}
+	})
+}
+
+func TestEngineNewJob(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should create jobs with the config of each engine", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+			Return(strings.NewReader("enabled: true"), nil).Times(2)
+
+		engineWith := func(tagRegexp string) *Engine {
+			_, mainConfig, err := config.LoadGlobalConfig("", nil)
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+			mainConfig.OrgConfig = nil
+			mainConfig.Globals.TagRegexp = tagRegexp
+
+			engine, err := NewEngine(Options{
+				Platform: mockPlatforms,
+				Config:   mainConfig,
+			})
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+			return engine
+		}
+
+		for _, tagRegexp := range []string{"v1.*", "v2.*"} {
+			job, err := engineWith(tagRegexp).NewJob("owner", "repository")
+			if err != nil {
+				t.Errorf("Error not expected: %#v", err)
+				continue
+			}
+			if job.Config.TagRegexp != tagRegexp {
+				t.Errorf("Expected tagRegexp %s, got %s", tagRegexp, job.Config.TagRegexp)
+			}
+		}
+	})
+
+	t.Run("should keep the main config of a job when the engine is updated",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+				Return(strings.NewReader("enabled: true"), nil)
+
+			_, mainConfig, err := config.LoadGlobalConfig("", nil)
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+			mainConfig.OrgConfig = nil
+			mainConfig.SignaturesDir = "/previous"
+
+			engine := newEngine(mockPlatforms, mainConfig, zerolog.Nop())
+			job, err := engine.NewJob("owner", "repository")
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+
+			engine.Update(mockPlatforms, &config.MainConfig{SignaturesDir: "/reloaded"})
+
+			if dir := job.mainConfig().SignaturesDir; dir != "/previous" {
+				t.Errorf("Expected signatures dir /previous, got %s", dir)
+			}
+		})
+
+	t.Run("should not share dependents between engines", func(t *testing.T) {
+		first := newEngine(nil, &config.MainConfig{}, zerolog.Nop())
+		second := newEngine(nil, &config.MainConfig{}, zerolog.Nop())
+
+		job := &Job{Owner: "owner", Repository: "frontend", engine: first}
+		job.registerDependent(&utils.Dependency{Owner: "owner", Repository: "backend"})
+
+		if diff := pretty.Compare(first.Dependents("owner", "backend"),
+			[]string{"owner/frontend"}); diff != "" {
+			t.Errorf("diff: (-got +want)\n%s", diff)
+		}
+
+		if dependents := second.Dependents("owner", "backend"); len(dependents) > 0 {
+			t.Errorf("Expected no dependents, got %#v", dependents)
+		}
+	})
+}
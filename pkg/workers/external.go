@@ -0,0 +1,105 @@
+package workers
+
+import (
+	"fmt"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// externalGateName is the name of the external decision gate displayed in the
+// release note
+const externalGateName = "grgate/external"
+
+// checkExternal send the release, its commit and its statuses to the
+// external decision service and map its decision to the gate state. Pending
+// decisions, as well as failing requests, are re-checked after the recheck
+// interval
+func (j *Job) checkExternal(release *platforms.Release) (result *gateResult, err error) {
+	result = &gateResult{
+		name:  externalGateName,
+		state: gateSucceeded,
+	}
+
+	if j.Config.External.URL == "" {
+		result.state = gateFailed
+		result.messages = append(result.messages, "external URL is undefined")
+		return result, nil
+	}
+
+	statusList, err := j.Platform.ListStatuses(j.Owner, j.Repository,
+		release.CommitSha)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't list release statuses")
+		return nil, err
+	}
+
+	commit, err := j.Platform.GetCommit(j.Owner, j.Repository, release.CommitSha)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't get release commit")
+		return nil, err
+	}
+
+	decision, err := utils.RequestExternalDecision(j.Config.External.URL,
+		j.mainConfig().ExternalSecret, &utils.ReleaseContext{
+			Owner:      j.Owner,
+			Repository: j.Repository,
+			Release: &utils.ReleaseContextRelease{
+				Name:      release.Name,
+				Tag:       release.Tag,
+				CommitSha: release.CommitSha,
+			},
+			Commit:   utils.NewReleaseContextCommit(commit),
+			Statuses: utils.NewReleaseContextStatuses(statusList),
+		})
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't get external decision")
+		decision = &utils.ExternalDecision{
+			Decision: utils.ExternalDecisionPending,
+			Reason:   fmt.Sprintf("couldn't get decision: %s", err),
+		}
+	}
+
+	switch decision.Decision {
+	case utils.ExternalDecisionDeny:
+		result.state = gateFailed
+	case utils.ExternalDecisionPending:
+		result.state = gatePending
+		result.recheckAfter = j.Config.External.RecheckInterval
+	}
+
+	if decision.Reason != "" && !result.succeeded() {
+		result.messages = append(result.messages, decision.Reason)
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("External gate: %s", result.state)
+
+	return result, nil
+}
@@ -0,0 +1,122 @@
+//go:build unit
+
+package workers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+func TestCheckExternal(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	testCases := []struct {
+		name                 string
+		response             string
+		expectedState        string
+		expectedMessages     []string
+		expectedRecheckAfter time.Duration
+	}{
+		{
+			name:          "should succeed if the release is allowed",
+			response:      `{"decision":"allow","reason":"looks good"}`,
+			expectedState: gateSucceeded,
+		},
+		{
+			name:             "should fail if the release is denied",
+			response:         `{"decision":"deny","reason":"change freeze"}`,
+			expectedState:    gateFailed,
+			expectedMessages: []string{"change freeze"},
+		},
+		{
+			name:                 "should be pending and re-checked if the decision is pending",
+			response:             `{"decision":"pending","reason":"waiting for approval"}`,
+			expectedState:        gatePending,
+			expectedMessages:     []string{"waiting for approval"},
+			expectedRecheckAfter: 10 * time.Minute,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					if r.Header.Get(utils.ExternalSignatureHeader) !=
+						utils.SignExternalPayload("external-secret", body) {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+
+					var request *utils.ReleaseContext
+					if err := json.Unmarshal(body, &request); err != nil ||
+						request.Commit == nil || request.Commit.Author != "John Doe" ||
+						request.Commit.Message != "fix: some bug" {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+
+					_, _ = w.Write([]byte(testCase.response))
+				}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+			mockPlatforms.EXPECT().ListStatuses(gomock.Any(), gomock.Any(),
+				gomock.Any()).Return([]*platforms.Status{
+				{Name: "e2e happy flow", Status: "completed", State: "success"},
+			}, nil)
+			mockPlatforms.EXPECT().GetCommit(gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(&platforms.Commit{
+				Sha:     "abcd1234",
+				Message: "fix: some bug",
+				Author:  "John Doe",
+			}, nil)
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					External: &config.External{
+						Enabled:         true,
+						URL:             server.URL,
+						RecheckInterval: 10 * time.Minute,
+					},
+				},
+				engine: newEngine(mockPlatforms, &config.MainConfig{
+					ExternalSecret: "external-secret",
+				}, zerolog.Nop()),
+			}
+
+			result, err := job.checkExternal(&platforms.Release{Tag: "v1.2.3"})
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+			if result.state != testCase.expectedState {
+				t.Errorf("Expected state %s, got %s", testCase.expectedState,
+					result.state)
+			}
+			if result.recheckAfter != testCase.expectedRecheckAfter {
+				t.Errorf("Expected recheck after %s, got %s",
+					testCase.expectedRecheckAfter, result.recheckAfter)
+			}
+			if diff := pretty.Compare(result.messages, testCase.expectedMessages); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package workers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+const (
+	// gateSucceeded is the state of a gate which allow the release to be
+	// published
+	gateSucceeded = "success"
+
+	// gatePending is the state of a gate which can't be evaluated yet
+	gatePending = "pending"
+
+	// gateFailed is the state of a gate which block the release
+	gateFailed = "failed"
+)
+
+// gateResult hold the result of a gate evaluated against a release
+type gateResult struct {
+	// name of the gate, displayed as a status in the release note
+	name string
+
+	// state of the gate, one of success, pending or failed
+	state string
+
+	// messages explaining why the gate didn't succeed
+	messages []string
+
+	// signature verified by the signature gate, exposed to the release note
+	// template
+	signature *platforms.Signature
+
+	// issues blocking the release found by the blockers gate, exposed to the
+	// release note template
+	issues []*platforms.Issue
+
+	// recheckAfter define when a pending gate should be evaluated again, 0 if
+	// the gate doesn't need to be re-checked
+	recheckAfter time.Duration
+}
+
+// succeeded returns true if the gate allow the release to be published
+func (g *gateResult) succeeded() bool {
+	return g.state == gateSucceeded
+}
+
+// reason returns the messages of the gate as a single line
+func (g *gateResult) reason() string {
+	if len(g.messages) == 0 {
+		return g.name + " " + g.state
+	}
+	return g.name + ": " + strings.Join(g.messages, ", ")
+}
+
+// status returns a synthetic status representing the gate, used to display the
+// gate in the release note
+func (g *gateResult) status(commitSha string) *platforms.Status {
+	return &platforms.Status{
+		CommitSha: commitSha,
+		Name:      g.name,
+		Status:    g.state,
+	}
+}
+
+// processGates evaluate all the gates enabled in the config against a release
+func (j *Job) processGates(release *platforms.Release) (results []*gateResult, err error) {
+	if j.Config.Assets != nil && j.Config.Assets.Enabled {
+		result, err := j.checkAssets(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if j.Config.Blockers != nil && j.Config.Blockers.Enabled {
+		result, err := j.checkBlockers(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if len(j.Config.DependsOn) > 0 {
+		result, err := j.checkDependencies(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if j.Config.External != nil && j.Config.External.Enabled {
+		result, err := j.checkExternal(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if j.Config.Metrics != nil && j.Config.Metrics.Enabled {
+		result, err := j.checkMetrics(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if len(j.Config.Plugins) > 0 {
+		pluginResults, err := j.checkPlugins(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, pluginResults...)
+	}
+
+	if j.Config.Policy != nil && j.Config.Policy.Enabled {
+		result, err := j.checkPolicy(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if j.Config.Signature != nil && j.Config.Signature.Enabled {
+		result, err := j.checkSignature(release)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -6,44 +6,118 @@ import (
 	"strings"
 	"time"
 
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 
 	"github.com/fikaworks/grgate/pkg/config"
 	"github.com/fikaworks/grgate/pkg/platforms"
 	"github.com/fikaworks/grgate/pkg/utils"
 )
 
-// Job define information about the job to process
+// Job define information about the job to process. RecheckAfter is set
+// during processing when a gate is pending and should be evaluated again
+// after the given duration. Jobs are created by Engine.NewJob, jobs without
+// an engine are not processed
 type Job struct {
-	Platform   platforms.Platform
-	Owner      string
-	Repository string
-	Config     *config.RepoConfig
+	Platform     platforms.Platform
+	Owner        string
+	Repository   string
+	Config       *config.RepoConfig
+	RecheckAfter time.Duration
+
+	engine *Engine
+
+	// main is the main config of the engine when the job was created, so that
+	// a job processed during a config reload doesn't mix both configs
+	main *config.MainConfig
 }
 
-// NewJob return a Job to be processed by a worker
-func NewJob(platform platforms.Platform, owner, repository string) (job *Job, err error) {
-	repoConfig, err := config.NewRepoConfig(platform, owner, repository)
-	if err != nil {
+// logger returns the logger of the job engine
+func (j *Job) logger() *zerolog.Logger {
+	return j.engine.Logger()
+}
+
+// mainConfig returns the main config the job was created with, or the current
+// main config of the engine if the job wasn't created by the engine
+func (j *Job) mainConfig() *config.MainConfig {
+	if j.main != nil {
+		return j.main
+	}
+	return j.engine.Config()
+}
+
+// statusSources returns the trusted source of the required statuses indexed by
+// status name
+func (j *Job) statusSources() map[string]*platforms.StatusSource {
+	sources := make(map[string]*platforms.StatusSource)
+	for _, rule := range j.Config.StatusRules {
+		if rule.Source == nil {
+			continue
+		}
+		sources[rule.Name] = &platforms.StatusSource{
+			App:            rule.Source.App,
+			Creator:        rule.Source.Creator,
+			PipelineSource: rule.Source.PipelineSource,
+		}
+	}
+	return sources
+}
+
+// requiredStatuses returns the statuses required to publish a release. When
+// statusesFrom is set to branchProtection, the statuses discovered from the
+// platform are merged with the statuses defined in config
+func (j *Job) requiredStatuses(release *platforms.Release) (statuses []string, err error) {
+	statuses = append(statuses, j.Config.Statuses...)
+
+	if j.Config.StatusesFrom != config.StatusesFromBranchProtection {
 		return
 	}
 
-	job = &Job{
-		Platform:   platform,
-		Owner:      owner,
-		Repository: repository,
-		Config:     repoConfig,
+	discovered, err := j.Platform.ListRequiredStatuses(j.Owner, j.Repository,
+		release)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't list required statuses from branch protection")
+		return nil, err
 	}
-	return
+
+	for _, status := range discovered {
+		found := false
+		for _, s := range statuses {
+			if s == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			statuses = append(statuses, status)
+		}
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Required statuses: %s", strings.Join(statuses, ", "))
+
+	return statuses, nil
 }
 
-// processReleaseNote update releases description with statuses based on the
-// release template defined in config
-func (j *Job) processReleaseNote(release *platforms.Release) (err error) {
+// processReleaseNote update releases description with statuses and gate
+// results based on the release template defined in config
+func (j *Job) processReleaseNote(release *platforms.Release, statuses []string,
+	gates []*gateResult) (err error) {
 	if !j.Config.ReleaseNote.Enabled {
 		return
 	}
-	log.Info().
+	j.logger().Info().
 		Str("repository", j.Repository).
 		Str("owner", j.Owner).
 		Str("releaseCommit", release.CommitSha).
@@ -54,7 +128,7 @@ func (j *Job) processReleaseNote(release *platforms.Release) (err error) {
 	statusList, err := j.Platform.ListStatuses(j.Owner, j.Repository,
 		release.CommitSha)
 	if err != nil {
-		log.Error().
+		j.logger().Error().
 			Err(err).
 			Str("owner", j.Owner).
 			Str("repository", j.Repository).
@@ -67,12 +141,21 @@ func (j *Job) processReleaseNote(release *platforms.Release) (err error) {
 
 	releaseNoteData := &utils.ReleaseNoteData{
 		ReleaseNote: release.ReleaseNote,
-		Statuses:    utils.MergeStatuses(statusList, j.Config.Statuses),
 	}
+
+	for _, gate := range gates {
+		statusList = append(statusList, gate.status(release.CommitSha))
+		if gate.signature != nil {
+			releaseNoteData.Signature = gate.signature
+		}
+		releaseNoteData.Blockers = append(releaseNoteData.Blockers, gate.issues...)
+	}
+
+	releaseNoteData.Statuses = utils.MergeStatuses(statusList, statuses)
 	release.ReleaseNote, err = utils.RenderReleaseNote(j.Config.ReleaseNote.Template,
 		releaseNoteData)
 	if err != nil {
-		log.Error().
+		j.logger().Error().
 			Err(err).
 			Str("owner", j.Owner).
 			Str("repository", j.Repository).
@@ -86,7 +169,7 @@ func (j *Job) processReleaseNote(release *platforms.Release) (err error) {
 	if j.Config.Enabled {
 		err = j.Platform.UpdateRelease(j.Owner, j.Repository, release)
 		if err != nil {
-			log.Error().
+			j.logger().Error().
 				Err(err).
 				Str("owner", j.Owner).
 				Str("repository", j.Repository).
@@ -97,7 +180,7 @@ func (j *Job) processReleaseNote(release *platforms.Release) (err error) {
 			return
 		}
 	} else {
-		log.Info().
+		j.logger().Info().
 			Str("repository", j.Repository).
 			Str("owner", j.Owner).
 			Str("releaseCommit", release.CommitSha).
@@ -122,39 +205,35 @@ func (j *Job) findIssueDashboard(issueList []*platforms.Issue) *platforms.Issue
 // processDashboard look for each issues created by the author in a repository,
 // then update issue with current GRGate state of the first issue matching the
 // dashboard title
-func (j *Job) processDashboard(errorList []string) {
+func (j *Job) processDashboard(dashboard *utils.DashboardData) {
 	if !j.Config.Dashboard.Enabled {
 		return
 	}
-	log.Info().
+	j.logger().Info().
 		Str("repository", j.Repository).
 		Str("owner", j.Owner).
 		Msg("Updating dashboard")
 
 	issueList, err := j.Platform.ListIssuesByAuthor(j.Owner, j.Repository, j.Config.Dashboard.Author)
 	if err != nil {
-		log.Error().
+		j.logger().Error().
 			Err(err).
 			Str("owner", j.Owner).
 			Str("repository", j.Repository).
 			Msg("Couldn't list issues")
 		return
 	}
-	log.Debug().
+	j.logger().Debug().
 		Str("owner", j.Owner).
 		Str("repository", j.Repository).
 		Msgf("Found %d dashboard issue(s)", len(issueList))
 
-	body, err := utils.RenderDashboard(
-		j.Config.Dashboard.Template,
-		&utils.DashboardData{
-			Enabled:           j.Config.Enabled,
-			Errors:            errorList,
-			LastExecutionTime: time.Now().UTC().Format(time.UnixDate),
-		},
-	)
+	dashboard.Enabled = j.Config.Enabled
+	dashboard.LastExecutionTime = time.Now().UTC().Format(time.UnixDate)
+
+	body, err := utils.RenderDashboard(j.Config.Dashboard.Template, dashboard)
 	if err != nil {
-		log.Error().
+		j.logger().Error().
 			Err(err).
 			Str("owner", j.Owner).
 			Str("repository", j.Repository).
@@ -167,7 +246,7 @@ func (j *Job) processDashboard(errorList []string) {
 		issue.Body = body
 		err = j.Platform.UpdateIssue(j.Owner, j.Repository, issue)
 		if err != nil {
-			log.Error().
+			j.logger().Error().
 				Err(err).
 				Str("owner", j.Owner).
 				Str("repository", j.Repository).
@@ -181,7 +260,7 @@ func (j *Job) processDashboard(errorList []string) {
 		}
 		err = j.Platform.CreateIssue(j.Owner, j.Repository, issue)
 		if err != nil {
-			log.Error().
+			j.logger().Error().
 				Err(err).
 				Str("owner", j.Owner).
 				Str("repository", j.Repository).
@@ -191,56 +270,185 @@ func (j *Job) processDashboard(errorList []string) {
 	}
 }
 
+// processRollback look for releases published within the rollback window
+// which have a required status that failed after publication, then revert
+// them to draft and record the reason in the release note
+func (j *Job) processRollback(tagRegexp *regexp.Regexp) (err error) {
+	if j.Config.Rollback == nil || !j.Config.Rollback.Enabled {
+		return
+	}
+
+	releaseList, err := j.Platform.ListReleases(j.Owner, j.Repository)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Msg("Couldn't list releases")
+		return
+	}
+
+	for _, release := range releaseList {
+		if release.Draft || release.PublishedAt.IsZero() ||
+			time.Since(release.PublishedAt) > j.Config.Rollback.Window ||
+			!tagRegexp.MatchString(release.Tag) {
+			continue
+		}
+
+		statuses, err := j.requiredStatuses(release)
+		if err != nil {
+			return err
+		}
+
+		// statuses which do not come from their trusted source are ignored so
+		// that a spoofed failure can't unpublish a release
+		failed, err := j.Platform.ListFailedStatuses(j.Owner, j.Repository,
+			release.CommitSha, statuses, j.statusSources())
+		if err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msg("Couldn't list release failed statuses")
+			return err
+		}
+
+		if len(failed) == 0 {
+			continue
+		}
+
+		reason := fmt.Sprintf("required status(es) failed after publication: %s",
+			strings.Join(failed, ", "))
+
+		if !j.Config.Enabled {
+			j.logger().Info().
+				Str("repository", j.Repository).
+				Str("owner", j.Owner).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("%s, would revert release to draft [dry-run]", reason)
+			continue
+		}
+
+		// the timeout starts again once the release is reverted to draft
+		release.ReleaseNote = utils.SetTimeoutStart(
+			utils.SetRollbackReason(release.ReleaseNote, reason), time.Now())
+
+		if _, err = j.Platform.UnpublishRelease(j.Owner, j.Repository, release); err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msg("Couldn't revert release to draft")
+			return err
+		}
+
+		j.logger().Info().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msgf("%s, release reverted to draft", reason)
+	}
+
+	return nil
+}
+
 // Process job by getting all the draft/unpublished releases, for each release
-// check that all the required status succeeded then publish the release
+// check that all the required status succeeded then publish the release. When
+// repoConfigMode is "release", each release is processed with the config read
+// from its commit while the tag regexp, rollback and dashboard settings are
+// read from the default branch. It returns an error if the job was not created
+// by an engine
 func (j *Job) Process() (err error) {
-	var errorDashboardList []string
+	if j.engine == nil {
+		return fmt.Errorf("job of %s/%s was not created by an engine", j.Owner,
+			j.Repository)
+	}
+
+	dashboard := &utils.DashboardData{}
 
 	defer func() {
-		j.processDashboard(errorDashboardList)
+		j.processDashboard(dashboard)
 	}()
 
-	log.Info().
+	j.logger().Info().
 		Str("repository", j.Repository).
 		Str("owner", j.Owner).
 		Msgf("Processing")
-	log.Info().
+	j.logger().Info().
 		Str("repository", j.Repository).
 		Str("owner", j.Owner).
 		Msgf("Dry run: %t", !j.Config.Enabled)
-	log.Info().
+	j.logger().Info().
 		Str("repository", j.Repository).
 		Str("owner", j.Owner).
 		Msgf("Matching statuses: %s", strings.Join(j.Config.Statuses, ", "))
-	log.Info().
+
+	if len(j.Config.Errors) > 0 {
+		j.logger().Error().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Msgf("Invalid repository config, skipping process: %s",
+				strings.Join(j.Config.Errors, ", "))
+		dashboard.Errors = append(dashboard.Errors, j.Config.Errors...)
+		return nil
+	}
+
+	// statuses are read from the config of each release
+	perRelease := j.releaseConfigEnabled()
+
+	if !perRelease && j.Config.StatusesFrom != "" &&
+		j.Config.StatusesFrom != config.StatusesFromBranchProtection {
+		j.logger().Error().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Msgf("Unsupported statusesFrom \"%s\", skipping process", j.Config.StatusesFrom)
+		dashboard.Errors = append(dashboard.Errors,
+			fmt.Sprintf("Unsupported statusesFrom \"%s\" in .grgate.yaml", j.Config.StatusesFrom))
+		return nil
+	}
+	j.logger().Info().
 		Str("repository", j.Repository).
 		Str("owner", j.Owner).
 		Msgf("Matching tag regexp: %s", j.Config.TagRegexp)
 
-	if len(j.Config.Statuses) == 0 {
-		log.Info().
+	if !perRelease && len(j.Config.Statuses) == 0 && j.Config.StatusesFrom == "" {
+		j.logger().Info().
 			Str("repository", j.Repository).
 			Str("owner", j.Owner).
 			Msg("Statuses are undefined in config, skipping process")
-		errorDashboardList = append(errorDashboardList, "Statuses are undefined in .grgate.yaml")
+		dashboard.Errors = append(dashboard.Errors, "Statuses are undefined in .grgate.yaml")
 		return nil
 	}
 
 	tagRegexp, err := regexp.Compile(j.Config.TagRegexp)
 	if err != nil {
-		log.Error().
+		j.logger().Error().
 			Err(err).
 			Str("owner", j.Owner).
 			Str("repository", j.Repository).
 			Msgf("Couldn't compile regexp \"%s\"", j.Config.TagRegexp)
-		errorDashboardList = append(errorDashboardList,
+		dashboard.Errors = append(dashboard.Errors,
 			fmt.Sprintf("Couldn't compile regexp \"%s\"", j.Config.TagRegexp))
 		return err
 	}
 
+	if err = j.processRollback(tagRegexp); err != nil {
+		return err
+	}
+
 	releaseList, err := j.Platform.ListDraftReleases(j.Owner, j.Repository)
 	if err != nil {
-		log.Error().
+		j.logger().Error().
 			Err(err).
 			Str("owner", j.Owner).
 			Str("repository", j.Repository).
@@ -248,99 +456,290 @@ func (j *Job) Process() (err error) {
 		return err
 	}
 
-	log.Info().
+	j.logger().Info().
 		Str("repository", j.Repository).
 		Str("owner", j.Owner).
 		Msgf("Found %d release(s) marked as draft", len(releaseList))
 
 	for _, release := range releaseList {
-		if !tagRegexp.MatchString(release.Tag) {
-			log.Debug().
-				Str("repository", j.Repository).
-				Str("owner", j.Owner).
-				Str("releaseCommit", release.CommitSha).
-				Str("releaseTag", release.Tag).
-				Str("releaseName", release.Name).
-				Msgf("Release do not match provided target tag %s", j.Config.TagRegexp)
-			continue
+		if err = j.processRelease(release, tagRegexp, dashboard); err != nil {
+			return err
 		}
+	}
 
-		log.Debug().
+	return nil
+}
+
+// processRelease check that all the required statuses and gates of a draft
+// release succeeded then publish the release
+func (j *Job) processRelease(release *platforms.Release, tagRegexp *regexp.Regexp,
+	dashboard *utils.DashboardData) error {
+	if !tagRegexp.MatchString(release.Tag) {
+		j.logger().Debug().
 			Str("repository", j.Repository).
 			Str("owner", j.Owner).
 			Str("releaseCommit", release.CommitSha).
 			Str("releaseTag", release.Tag).
 			Str("releaseName", release.Name).
-			Msgf("Release match provided target tag %s", j.Config.TagRegexp)
+			Msgf("Release do not match provided target tag %s", j.Config.TagRegexp)
+		return nil
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Release match provided target tag %s", j.Config.TagRegexp)
+
+	if reason := utils.GetRollbackReason(release.ReleaseNote); reason != "" {
+		dashboard.RolledBackReleases = append(dashboard.RolledBackReleases,
+			&utils.DashboardRelease{
+				Name:   release.Name,
+				Reason: reason,
+				Tag:    release.Tag,
+			})
+	}
 
-		succeeded, err := j.Platform.CheckAllStatusSucceeded(j.Owner,
-			j.Repository, release.CommitSha, j.Config.Statuses)
+	if j.releaseConfigEnabled() {
+		releaseConfig, err := j.releaseConfig(release, dashboard)
 		if err != nil {
-			log.Error().
-				Err(err).
-				Str("owner", j.Owner).
-				Str("repository", j.Repository).
-				Str("releaseCommit", release.CommitSha).
-				Str("releaseTag", release.Tag).
-				Str("releaseName", release.Name).
-				Msg("Couldn't check all status check")
 			return err
 		}
+		if releaseConfig == nil {
+			return nil
+		}
+
+		// the default branch config is restored for the next releases
+		defaultConfig := j.Config
+		j.Config = releaseConfig
+		defer func() {
+			j.Config = defaultConfig
+		}()
+	}
+
+	blocked, err := j.processTimedOutRelease(release, dashboard)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return nil
+	}
+
+	if err = j.processTriggers(release); err != nil {
+		return err
+	}
+
+	statuses, err := j.requiredStatuses(release)
+	if err != nil {
+		return err
+	}
+
+	if len(statuses) == 0 {
+		j.logger().Info().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("No required status found, skipping release")
+		dashboard.BlockedReleases = append(dashboard.BlockedReleases,
+			&utils.DashboardRelease{
+				Name:   release.Name,
+				Reason: "no required status found in branch protection rules",
+				Tag:    release.Tag,
+			})
+		return nil
+	}
 
-		if err = j.processReleaseNote(release); err != nil {
+	succeeded, err := j.Platform.CheckAllStatusSucceeded(j.Owner,
+		j.Repository, release.CommitSha, statuses, j.statusSources())
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't check all status check")
+		return err
+	}
+
+	if !succeeded {
+		if err = j.processRetries(release, statuses, dashboard); err != nil {
 			return err
 		}
+	}
 
-		log.Trace().
-			Str("repository", j.Repository).
+	gates, err := j.processGates(release)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
 			Str("owner", j.Owner).
+			Str("repository", j.Repository).
 			Str("releaseCommit", release.CommitSha).
 			Str("releaseTag", release.Tag).
 			Str("releaseName", release.Name).
-			Msgf("CheckAllStatusSucceeded: %t", succeeded)
-
-		if succeeded {
-			if !j.Config.Enabled {
-				log.Info().
-					Str("repository", j.Repository).
-					Str("owner", j.Owner).
-					Str("releaseCommit", release.CommitSha).
-					Str("releaseTag", release.Tag).
-					Str("releaseName", release.Name).
-					Msgf("All required status succeeded, would publish release [dry-run]")
-				continue
-			}
+			Msg("Couldn't evaluate gates")
+		return err
+	}
 
-			log.Debug().
+	for _, gate := range gates {
+		if gate.recheckAfter > 0 &&
+			(j.RecheckAfter == 0 || gate.recheckAfter < j.RecheckAfter) {
+			j.RecheckAfter = gate.recheckAfter
+		}
+		if gate.succeeded() {
+			continue
+		}
+		succeeded = false
+		dashboard.BlockedReleases = append(dashboard.BlockedReleases,
+			&utils.DashboardRelease{
+				Name:   release.Name,
+				Reason: gate.reason(),
+				Tag:    release.Tag,
+			})
+	}
+
+	if !succeeded {
+		if err = j.processTimeout(release, dashboard); err != nil {
+			return err
+		}
+	}
+
+	if err = j.processReleaseNote(release, statuses, gates); err != nil {
+		return err
+	}
+
+	j.logger().Trace().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("CheckAllStatusSucceeded: %t", succeeded)
+
+	if succeeded {
+		now := time.Now()
+		if reason, wait := j.reservePublish(now, j.Config.Enabled); reason != "" {
+			j.logger().Info().
 				Str("repository", j.Repository).
 				Str("owner", j.Owner).
 				Str("releaseCommit", release.CommitSha).
 				Str("releaseTag", release.Tag).
 				Str("releaseName", release.Name).
-				Msg("All required status succeeded, publishing release...")
-
-			_, err := j.Platform.PublishRelease(j.Owner, j.Repository, release)
-			if err != nil {
-				log.Error().
-					Err(err).
-					Str("owner", j.Owner).
-					Str("repository", j.Repository).
-					Str("releaseCommit", release.CommitSha).
-					Str("releaseTag", release.Tag).
-					Str("releaseName", release.Name).
-					Msg("Couldn't publish release")
-				return err
+				Msgf("All required status succeeded, %s, release queued", reason)
+			dashboard.QueuedReleases = append(dashboard.QueuedReleases,
+				&utils.DashboardRelease{
+					Name:   release.Name,
+					Reason: reason,
+					Tag:    release.Tag,
+				})
+			if j.RecheckAfter == 0 || wait < j.RecheckAfter {
+				j.RecheckAfter = wait
 			}
+			return nil
+		}
 
-			log.Info().
+		if !j.Config.Enabled {
+			j.logger().Info().
 				Str("repository", j.Repository).
 				Str("owner", j.Owner).
 				Str("releaseCommit", release.CommitSha).
 				Str("releaseTag", release.Tag).
 				Str("releaseName", release.Name).
-				Msg("Successfully published release")
+				Msgf("All required status succeeded, would publish release [dry-run]")
+			return nil
 		}
+
+		j.logger().Debug().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("All required status succeeded, publishing release...")
+
+		_, err := j.Platform.PublishRelease(j.Owner, j.Repository, release)
+		if err != nil {
+			j.cancelPublish(now)
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msg("Couldn't publish release")
+			return err
+		}
+
+		j.logger().Info().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Successfully published release")
 	}
 
 	return nil
 }
+
+// releaseConfigEnabled returns true if the repository config of each release
+// is read from the release commit
+func (j *Job) releaseConfigEnabled() bool {
+	return j.mainConfig().RepoConfigMode == config.RepoConfigModeRelease
+}
+
+// releaseConfig returns the repository config read from the release commit.
+// It returns nil if the release can't be processed with this config, the
+// reason is then reported in the dashboard
+func (j *Job) releaseConfig(release *platforms.Release,
+	dashboard *utils.DashboardData) (*config.RepoConfig, error) {
+	repoConfig, err := j.engine.Loader().RepoConfigAtRef(j.Platform, j.Owner,
+		j.Repository, release.CommitSha)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't read release config")
+		return nil, err
+	}
+
+	var errors []string
+	switch {
+	case len(repoConfig.Errors) > 0:
+		errors = repoConfig.Errors
+	case repoConfig.StatusesFrom != "" &&
+		repoConfig.StatusesFrom != config.StatusesFromBranchProtection:
+		errors = []string{fmt.Sprintf("Unsupported statusesFrom \"%s\" in .grgate.yaml",
+			repoConfig.StatusesFrom)}
+	case len(repoConfig.Statuses) == 0 && repoConfig.StatusesFrom == "":
+		errors = []string{"Statuses are undefined in .grgate.yaml"}
+	}
+
+	if len(errors) > 0 {
+		j.logger().Error().
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msgf("Invalid release config, skipping release: %s",
+				strings.Join(errors, ", "))
+		for _, e := range errors {
+			dashboard.Errors = append(dashboard.Errors,
+				fmt.Sprintf("%s (release %s)", e, release.Tag))
+		}
+		return nil, nil
+	}
+
+	return repoConfig, nil
+}
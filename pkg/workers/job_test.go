@@ -3,7 +3,10 @@
 package workers
 
 import (
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/kylelemons/godebug/pretty"
@@ -12,11 +15,25 @@ import (
 	"github.com/fikaworks/grgate/pkg/config"
 	"github.com/fikaworks/grgate/pkg/platforms"
 	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+	"github.com/fikaworks/grgate/pkg/utils"
 )
 
 func TestProcess(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 
+	t.Run("should return an error if the job was not created by an engine",
+		func(t *testing.T) {
+			job := &Job{
+				Owner:      "owner",
+				Repository: "repository",
+				Config:     &config.RepoConfig{Enabled: true},
+			}
+
+			if err := job.Process(); err == nil {
+				t.Errorf("Expected job without engine to return an error")
+			}
+		})
+
 	t.Run("should not process the repository if disabled by config",
 		func(t *testing.T) {
 			job := &Job{
@@ -26,6 +43,7 @@ func TestProcess(t *testing.T) {
 						Enabled: false,
 					},
 				},
+				engine: newTestEngine(nil),
 			}
 
 			if err := job.Process(); err != nil {
@@ -58,8 +76,9 @@ func TestProcess(t *testing.T) {
 					})
 
 			mockPlatforms.EXPECT().CheckAllStatusSucceeded(gomock.Any(), gomock.Any(),
-				gomock.Any(), gomock.Any()).DoAndReturn(
-				func(_ string, _ string, _ string, _ []string) (bool, error) {
+				gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ string, _ string, _ []string,
+					_ map[string]*platforms.StatusSource) (bool, error) {
 					return true, nil
 				})
 
@@ -82,6 +101,7 @@ func TestProcess(t *testing.T) {
 						Enabled: false,
 					},
 				},
+				engine: newTestEngine(mockPlatforms),
 			}
 
 			if err := job.Process(); err != nil {
@@ -109,8 +129,9 @@ func TestProcess(t *testing.T) {
 					})
 
 			mockPlatforms.EXPECT().CheckAllStatusSucceeded(gomock.Any(), gomock.Any(),
-				gomock.Any(), gomock.Any()).DoAndReturn(
-				func(_ string, _ string, _ string, _ []string) (bool, error) {
+				gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ string, _ string, _ []string,
+					_ map[string]*platforms.StatusSource) (bool, error) {
 					return true, nil
 				})
 
@@ -127,6 +148,7 @@ func TestProcess(t *testing.T) {
 						Enabled: false,
 					},
 				},
+				engine: newTestEngine(mockPlatforms),
 			}
 
 			if err := job.Process(); err != nil {
@@ -154,8 +176,9 @@ func TestProcess(t *testing.T) {
 					})
 
 			mockPlatforms.EXPECT().CheckAllStatusSucceeded(gomock.Any(), gomock.Any(),
-				gomock.Any(), gomock.Any()).DoAndReturn(
-				func(_ string, _ string, _ string, _ []string) (bool, error) {
+				gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ string, _ string, _ []string,
+					_ map[string]*platforms.StatusSource) (bool, error) {
 					return false, nil
 				})
 
@@ -172,11 +195,216 @@ func TestProcess(t *testing.T) {
 						Enabled: false,
 					},
 				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			if err := job.Process(); err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+		})
+
+	t.Run("should process each release with the config of its commit",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, mainConfig, err := config.LoadGlobalConfig("", nil)
+			if err != nil {
+				t.Fatalf("error not expected: %#v", err)
+			}
+			mainConfig.OrgConfig = nil
+			mainConfig.RepoConfigMode = config.RepoConfigModeRelease
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			engine, err := NewEngine(Options{
+				Platform: mockPlatforms,
+				Config:   mainConfig,
+			})
+			if err != nil {
+				t.Fatalf("error not expected: %#v", err)
+			}
+
+			mockPlatforms.EXPECT().ListDraftReleases(gomock.Any(), gomock.Any()).
+				Return([]*platforms.Release{
+					{
+						ID:        1,
+						Tag:       "v1.0.1",
+						CommitSha: "sha-1",
+					},
+					{
+						ID:        2,
+						Tag:       "v2.0.0",
+						CommitSha: "sha-2",
+					},
+				}, nil)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "sha-1").
+				Return(strings.NewReader(`statuses:
+  - legacy flow
+releaseNote:
+  enabled: false`), nil)
+
+			mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "sha-2").
+				Return(strings.NewReader(`releaseNote:
+  enabled: false`), nil)
+
+			mockPlatforms.EXPECT().CheckAllStatusSucceeded("owner", "repository",
+				"sha-1", []string{"legacy flow"}, gomock.Any()).
+				Return(true, nil)
+
+			mockPlatforms.EXPECT().PublishRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, release *platforms.Release) (bool, error) {
+					if release.ID != 1 {
+						t.Errorf("Expected release 1 to be published, got %v", release.ID)
+					}
+					return true, nil
+				})
+
+			job := &Job{
+				Platform:   mockPlatforms,
+				Owner:      "owner",
+				Repository: "repository",
+				Config: &config.RepoConfig{
+					Enabled:   true,
+					TagRegexp: ".*",
+					Dashboard: &config.Dashboard{
+						Enabled: false,
+					},
+				},
+				engine: engine,
 			}
 
 			if err := job.Process(); err != nil {
 				t.Errorf("error not expected: %#v", err)
 			}
+
+			if job.Config.Statuses != nil {
+				t.Errorf("Expected the default branch config to be restored")
+			}
+		})
+}
+
+func TestProcessRollback(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should revert published release to draft when a required status failed",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ListReleases(gomock.Any(), gomock.Any()).
+				DoAndReturn(
+					func(_ string, _ string) ([]*platforms.Release, error) {
+						return []*platforms.Release{
+							{
+								ID:          1,
+								Tag:         "v1.2.3",
+								PublishedAt: time.Now().Add(-time.Hour),
+							},
+							{
+								ID:          2,
+								Tag:         "v1.2.2",
+								PublishedAt: time.Now().Add(-48 * time.Hour),
+							},
+							{
+								ID:    3,
+								Tag:   "v1.2.4",
+								Draft: true,
+							},
+						}, nil
+					})
+
+			mockPlatforms.EXPECT().ListFailedStatuses(gomock.Any(), gomock.Any(),
+				gomock.Any(), []string{"happy flow"}, map[string]*platforms.StatusSource{
+					"happy flow": {App: "ci"},
+				}).Return([]string{"happy flow"}, nil)
+
+			mockPlatforms.EXPECT().UnpublishRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, release *platforms.Release) (bool, error) {
+					if release.ID != 1 {
+						t.Errorf("Expected release 1 to be reverted, got %v", release.ID)
+					}
+					expectedReason := "required status(es) failed after publication: happy flow"
+					if reason := utils.GetRollbackReason(release.ReleaseNote); reason != expectedReason {
+						t.Errorf("Expected reason %q, got %q", expectedReason, reason)
+					}
+					if utils.GetTimeoutStart(release.ReleaseNote).IsZero() {
+						t.Errorf("Expected the timeout to start again")
+					}
+					return true, nil
+				})
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Enabled:  true,
+					Statuses: []string{"happy flow"},
+					StatusRules: []*config.StatusRule{
+						{Name: "happy flow", Source: &config.StatusSource{App: "ci"}},
+					},
+					Rollback: &config.Rollback{
+						Enabled: true,
+						Window:  24 * time.Hour,
+					},
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			if err := job.processRollback(regexp.MustCompile(".*")); err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+		})
+
+	t.Run("should not revert published release when only an untrusted status failed",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ListReleases(gomock.Any(), gomock.Any()).
+				Return([]*platforms.Release{
+					{
+						ID:          1,
+						Tag:         "v1.2.3",
+						PublishedAt: time.Now().Add(-time.Hour),
+					},
+				}, nil)
+
+			// the failing "happy flow" status reported by another app is
+			// filtered out by the platform
+			mockPlatforms.EXPECT().ListFailedStatuses(gomock.Any(), gomock.Any(),
+				gomock.Any(), []string{"happy flow"}, map[string]*platforms.StatusSource{
+					"happy flow": {App: "ci"},
+				}).Return(nil, nil)
+
+			mockPlatforms.EXPECT().UnpublishRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).Times(0)
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Enabled:  true,
+					Statuses: []string{"happy flow"},
+					StatusRules: []*config.StatusRule{
+						{Name: "happy flow", Source: &config.StatusSource{App: "ci"}},
+					},
+					Rollback: &config.Rollback{
+						Enabled: true,
+						Window:  24 * time.Hour,
+					},
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			if err := job.processRollback(regexp.MustCompile(".*")); err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
 		})
 }
 
@@ -270,6 +498,7 @@ func TestProcessReleaseNote(t *testing.T) {
 <!-- GRGate end -->`,
 					},
 				},
+				engine: newTestEngine(mockPlatforms),
 			}
 
 			releaseList := &platforms.Release{
@@ -278,8 +507,45 @@ func TestProcessReleaseNote(t *testing.T) {
 				ReleaseNote: "This is a release note",
 			}
 
-			if err := job.processReleaseNote(releaseList); err != nil {
+			if err := job.processReleaseNote(releaseList, job.Config.Statuses, nil); err != nil {
 				t.Errorf("error not expected: %#v", err)
 			}
 		})
 }
+
+func TestRequiredStatuses(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should merge statuses discovered from branch protection",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ListRequiredStatuses(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, _ *platforms.Release) ([]string, error) {
+					return []string{"happy flow", "user account flow"}, nil
+				})
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Statuses:     []string{"happy flow", "security scan"},
+					StatusesFrom: config.StatusesFromBranchProtection,
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			result, err := job.requiredStatuses(&platforms.Release{Tag: "v1.2.3"})
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			expected := []string{"happy flow", "security scan", "user account flow"}
+			if diff := pretty.Compare(result, expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
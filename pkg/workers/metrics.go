@@ -0,0 +1,109 @@
+package workers
+
+import (
+	"fmt"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// metricsGateName is the name of the metrics gate displayed in the release
+// note
+const metricsGateName = "grgate/metrics"
+
+// checkMetrics run each metric query against the Prometheus API and compare
+// the result with its threshold. The gate stay pending until all the queries
+// satisfy their threshold
+func (j *Job) checkMetrics(release *platforms.Release) (result *gateResult, err error) {
+	result = &gateResult{
+		name:  metricsGateName,
+		state: gateSucceeded,
+	}
+
+	if j.Config.Metrics.URL == "" {
+		result.state = gateFailed
+		result.messages = append(result.messages, "metrics URL is undefined")
+		return result, nil
+	}
+
+	data := &utils.TemplateData{
+		CommitSha:  release.CommitSha,
+		Name:       release.Name,
+		Owner:      j.Owner,
+		Repository: j.Repository,
+		Tag:        release.Tag,
+	}
+
+	for _, metricQuery := range j.Config.Metrics.Queries {
+		message, err := j.evaluateMetricQuery(metricQuery.Query, metricQuery.Operator,
+			metricQuery.Threshold, data)
+		if err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("Couldn't evaluate metric query %s", metricQuery.Name)
+			message = fmt.Sprintf("couldn't evaluate query: %s", err)
+		}
+
+		if message == "" {
+			continue
+		}
+
+		if result.state == gateSucceeded {
+			result.state = gatePending
+		}
+		result.messages = append(result.messages,
+			fmt.Sprintf("%s %s", metricQuery.Name, message))
+	}
+
+	if result.state == gatePending {
+		result.recheckAfter = j.Config.Metrics.RecheckInterval
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Metrics gate: %s", result.state)
+
+	return result, nil
+}
+
+// evaluateMetricQuery render and run a query, then compare each returned
+// value with the threshold. Returns a message if the threshold is not
+// satisfied
+func (j *Job) evaluateMetricQuery(tpl, operator string, threshold float64,
+	data *utils.TemplateData) (message string, err error) {
+	query, err := utils.RenderTemplate(tpl, data)
+	if err != nil {
+		return "", err
+	}
+
+	values, err := utils.QueryPrometheus(j.Config.Metrics.URL, query)
+	if err != nil {
+		return "", err
+	}
+
+	if len(values) == 0 {
+		return "returned no data", nil
+	}
+
+	for _, value := range values {
+		satisfied, err := utils.CompareThreshold(value, operator, threshold)
+		if err != nil {
+			return "", err
+		}
+		if !satisfied {
+			return fmt.Sprintf("is %g, expected %s %g", value, operator,
+				threshold), nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,78 @@
+//go:build unit
+
+package workers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+)
+
+func TestCheckMetrics(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var value string
+			switch r.URL.Query().Get("query") {
+			case `error_rate{version="v1.2.3"}`:
+				value = "0.01"
+			case `latency_p99{version="v1.2.3"}`:
+				value = "0.8"
+			}
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector",` +
+				`"result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+		}))
+	defer server.Close()
+
+	job := &Job{
+		Config: &config.RepoConfig{
+			Metrics: &config.Metrics{
+				Enabled: true,
+				URL:     server.URL,
+				Queries: []*config.MetricQuery{
+					{
+						Name:      "error rate",
+						Query:     `error_rate{version="{{ .Tag }}"}`,
+						Operator:  "<",
+						Threshold: 0.05,
+					},
+					{
+						Name:      "latency",
+						Query:     `latency_p99{version="{{ .Tag }}"}`,
+						Operator:  "<",
+						Threshold: 0.5,
+					},
+				},
+				RecheckInterval: 2 * time.Minute,
+			},
+		},
+		engine: newTestEngine(nil),
+	}
+
+	result, err := job.checkMetrics(&platforms.Release{Tag: "v1.2.3"})
+	if err != nil {
+		t.Errorf("error not expected: %#v", err)
+	}
+
+	if result.state != gatePending {
+		t.Errorf("Expected metrics gate to be pending, got %s", result.state)
+	}
+
+	if result.recheckAfter != 2*time.Minute {
+		t.Errorf("Expected metrics gate to be rechecked after 2m, got %s",
+			result.recheckAfter)
+	}
+
+	expected := []string{"latency is 0.8, expected < 0.5"}
+	if diff := pretty.Compare(result.messages, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,140 @@
+package workers
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// pluginGatePrefix is the prefix of the plugin gate names displayed in the
+// release note, followed by the plugin name
+const pluginGatePrefix = "grgate/plugin/"
+
+// pluginPath returns the path of the plugin executable. Plugins are resolved
+// from the plugins directory only, commands containing a path are rejected so
+// that a repository config can't run arbitrary executables
+func pluginPath(pluginsDir string, plugin *config.Plugin) (string, error) {
+	if pluginsDir == "" {
+		return "", fmt.Errorf("plugins directory is undefined")
+	}
+
+	if plugin.Command == "" || plugin.Command == "." || plugin.Command == ".." ||
+		strings.ContainsAny(plugin.Command, `/\`) {
+		return "", fmt.Errorf("invalid plugin command \"%s\"", plugin.Command)
+	}
+
+	return filepath.Join(pluginsDir, plugin.Command), nil
+}
+
+// checkPlugins run each plugin defined in config and returns a gate result per
+// plugin based on the verdict of the plugin. Plugins which can't be run block
+// the release, pending plugins are run again after the recheck interval
+func (j *Job) checkPlugins(release *platforms.Release) (results []*gateResult, err error) {
+	statusList, err := j.Platform.ListStatuses(j.Owner, j.Repository,
+		release.CommitSha)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't list release statuses")
+		return nil, err
+	}
+
+	input := &utils.ReleaseContext{
+		Owner:      j.Owner,
+		Repository: j.Repository,
+		Release: &utils.ReleaseContextRelease{
+			Name:      release.Name,
+			Tag:       release.Tag,
+			CommitSha: release.CommitSha,
+		},
+		Statuses: utils.NewReleaseContextStatuses(statusList),
+	}
+
+	for _, plugin := range j.Config.Plugins {
+		results = append(results, j.runPlugin(release, plugin, input))
+	}
+
+	return results, nil
+}
+
+// runPlugin run a plugin and map its verdict to a gate result, the timeout
+// defined by the repository is capped by the pluginMaxTimeout setting
+func (j *Job) runPlugin(release *platforms.Release, plugin *config.Plugin,
+	input *utils.ReleaseContext) (result *gateResult) {
+	result = &gateResult{
+		name:  pluginGatePrefix + plugin.Name,
+		state: gateSucceeded,
+	}
+
+	path, err := pluginPath(j.mainConfig().PluginsDir, plugin)
+	if err != nil {
+		result.state = gateFailed
+		result.messages = append(result.messages, err.Error())
+		return
+	}
+
+	timeout := plugin.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultPluginTimeout
+	}
+	if maxTimeout := j.mainConfig().PluginMaxTimeout; maxTimeout > 0 &&
+		timeout > maxTimeout {
+		timeout = maxTimeout
+	}
+
+	var env []string
+	for _, e := range plugin.Env {
+		if err := config.CheckPluginEnv(e.Name); err != nil {
+			result.state = gateFailed
+			result.messages = append(result.messages, err.Error())
+			return
+		}
+		env = append(env, e.Name+"="+e.Value)
+	}
+
+	verdict, err := utils.RunPlugin(path, plugin.Args, env, timeout, input)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msgf("Couldn't run plugin %s", plugin.Name)
+		result.state = gateFailed
+		result.messages = append(result.messages, err.Error())
+		return
+	}
+
+	switch verdict.State {
+	case utils.PluginStateFailure:
+		result.state = gateFailed
+	case utils.PluginStatePending:
+		result.state = gatePending
+		result.recheckAfter = j.Config.PluginsRecheckInterval
+	}
+
+	if verdict.Message != "" && !result.succeeded() {
+		result.messages = append(result.messages, verdict.Message)
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Plugin %s gate: %s", plugin.Name, result.state)
+
+	return
+}
@@ -0,0 +1,105 @@
+//go:build unit
+
+package workers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestCheckPlugins(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	pluginsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(pluginsDir, "license-scan"),
+		[]byte("#!/bin/sh\necho '{\"state\":\"failure\",\"message\":\"GPL-3.0 found\"}'\n"),
+		0o755); err != nil {
+		t.Fatalf("Error not expected: %#v", err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginsDir, "sbom-diff"),
+		[]byte("#!/bin/sh\necho '{\"state\":\"success\"}'\n"), 0o755); err != nil {
+		t.Fatalf("Error not expected: %#v", err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginsDir, "approval"),
+		[]byte("#!/bin/sh\necho '{\"state\":\"pending\",\"message\":\"waiting for approval\"}'\n"),
+		0o755); err != nil {
+		t.Fatalf("Error not expected: %#v", err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginsDir, "slow-scan"),
+		[]byte("#!/bin/sh\nsleep 5\n"), 0o755); err != nil {
+		t.Fatalf("Error not expected: %#v", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+	mockPlatforms.EXPECT().ListStatuses(gomock.Any(), gomock.Any(),
+		gomock.Any()).Return([]*platforms.Status{}, nil)
+
+	job := &Job{
+		Platform: mockPlatforms,
+		Config: &config.RepoConfig{
+			Plugins: []*config.Plugin{
+				{Name: "license", Command: "license-scan"},
+				{Name: "sbom", Command: "sbom-diff"},
+				{Name: "escape", Command: "../bin/sh"},
+				{
+					Name:    "preload",
+					Command: "sbom-diff",
+					Env:     []*config.PluginEnv{{Name: "LD_PRELOAD", Value: "/tmp/lib.so"}},
+				},
+				{Name: "slow", Command: "slow-scan", Timeout: time.Hour},
+				{Name: "approval", Command: "approval"},
+			},
+			PluginsRecheckInterval: 15 * time.Minute,
+		},
+		engine: newEngine(mockPlatforms, &config.MainConfig{
+			PluginMaxTimeout: 100 * time.Millisecond,
+			PluginsDir:       pluginsDir,
+		}, zerolog.Nop()),
+	}
+
+	results, err := job.checkPlugins(&platforms.Release{Tag: "v1.2.3"})
+	if err != nil {
+		t.Errorf("error not expected: %#v", err)
+	}
+
+	var reasons []string
+	for _, result := range results {
+		reasons = append(reasons, result.state+" "+result.reason())
+	}
+
+	expected := []string{
+		"failed grgate/plugin/license: GPL-3.0 found",
+		"success grgate/plugin/sbom success",
+		"failed grgate/plugin/escape: invalid plugin command \"../bin/sh\"",
+		"failed grgate/plugin/preload: environment variable LD_PRELOAD is reserved",
+		"failed grgate/plugin/slow: plugin timed out after 100ms",
+		"pending grgate/plugin/approval: waiting for approval",
+	}
+	if diff := pretty.Compare(reasons, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+
+	for _, result := range results {
+		expectedRecheckAfter := time.Duration(0)
+		if result.state == gatePending {
+			expectedRecheckAfter = 15 * time.Minute
+		}
+		if result.recheckAfter != expectedRecheckAfter {
+			t.Errorf("Expected %s to be rechecked after %s, got %s", result.name,
+				expectedRecheckAfter, result.recheckAfter)
+		}
+	}
+}
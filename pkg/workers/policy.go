@@ -0,0 +1,129 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// policyGateName is the name of the policy gate displayed in the release note
+const policyGateName = "grgate/policy"
+
+// previousTag returns the highest published release tag lower than the tag of
+// the release, an empty string is returned if there is no such release
+func (j *Job) previousTag(release *platforms.Release) (tag string, err error) {
+	releaseList, err := j.Platform.ListReleases(j.Owner, j.Repository)
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range releaseList {
+		if r.Draft {
+			continue
+		}
+		if result, err := utils.CompareVersions(r.Tag, release.Tag); err != nil || result >= 0 {
+			continue
+		}
+		if tag != "" {
+			if result, _ := utils.CompareVersions(r.Tag, tag); result <= 0 {
+				continue
+			}
+		}
+		tag = r.Tag
+	}
+
+	return tag, nil
+}
+
+// policyInput returns the data exposed to the policy rules
+func (j *Job) policyInput(release *platforms.Release) (input *utils.PolicyInput, err error) {
+	input = &utils.PolicyInput{
+		Release: release,
+		Now:     time.Now().UTC(),
+	}
+
+	if input.Statuses, err = j.Platform.ListStatuses(j.Owner, j.Repository,
+		release.CommitSha); err != nil {
+		return nil, fmt.Errorf("couldn't list release statuses: %w", err)
+	}
+
+	if input.Commit, err = j.Platform.GetCommit(j.Owner, j.Repository,
+		release.CommitSha); err != nil {
+		return nil, fmt.Errorf("couldn't get release commit: %w", err)
+	}
+
+	if input.PreviousTag, err = j.previousTag(release); err != nil {
+		return nil, fmt.Errorf("couldn't list releases: %w", err)
+	}
+
+	return input, nil
+}
+
+// checkPolicy evaluate each policy rule against the release. A rule denying
+// the release block it, a rule returning false or pending hold it until the
+// rule is satisfied
+func (j *Job) checkPolicy(release *platforms.Release) (result *gateResult, err error) {
+	result = &gateResult{
+		name:  policyGateName,
+		state: gateSucceeded,
+	}
+
+	if len(j.Config.Policy.Rules) == 0 {
+		return result, nil
+	}
+
+	input, err := j.policyInput(release)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't collect policy input")
+		return nil, err
+	}
+
+	for _, rule := range j.Config.Policy.Rules {
+		decision, err := utils.EvaluatePolicy(rule.Expression, input)
+		if err != nil {
+			result.state = gateFailed
+			result.messages = append(result.messages,
+				fmt.Sprintf("%s: %s", rule.Name, err))
+			continue
+		}
+
+		if decision == utils.PolicyDecisionAllow {
+			continue
+		}
+
+		if decision == utils.PolicyDecisionDeny {
+			result.state = gateFailed
+		} else if result.state == gateSucceeded {
+			result.state = gatePending
+		}
+
+		message := rule.Message
+		if message == "" {
+			message = fmt.Sprintf("rule %s not satisfied", rule.Name)
+		}
+		result.messages = append(result.messages, message)
+	}
+
+	if result.state == gatePending {
+		result.recheckAfter = j.Config.Policy.RecheckInterval
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Policy gate: %s", result.state)
+
+	return result, nil
+}
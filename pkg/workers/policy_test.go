@@ -0,0 +1,92 @@
+//go:build unit
+
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+	mockPlatforms.EXPECT().ListStatuses(gomock.Any(), gomock.Any(),
+		gomock.Any()).Return([]*platforms.Status{
+		{Name: "e2e", Status: "completed", State: "success"},
+	}, nil)
+
+	mockPlatforms.EXPECT().GetCommit(gomock.Any(), gomock.Any(),
+		gomock.Any()).Return(&platforms.Commit{Sha: "abcd1234"}, nil)
+
+	mockPlatforms.EXPECT().ListReleases(gomock.Any(), gomock.Any()).Return(
+		[]*platforms.Release{
+			{Tag: "v2.0.0", Draft: true},
+			{Tag: "v1.4.2"},
+			{Tag: "v1.10.0"},
+			{Tag: "v1.9.0"},
+		}, nil)
+
+	job := &Job{
+		Platform: mockPlatforms,
+		Config: &config.RepoConfig{
+			Policy: &config.Policy{
+				Enabled: true,
+				Rules: []*config.PolicyRule{
+					{
+						Name:       "e2e",
+						Expression: `statuses["e2e"] == "success"`,
+					},
+					{
+						Name:       "major-soak",
+						Expression: `release.bump != "major" || now - release.createdAt > duration("24h")`,
+						Message:    "major releases require a 24h soak",
+					},
+					{
+						Name:       "previous",
+						Expression: `release.previousTag == "v1.9.0"`,
+					},
+				},
+				RecheckInterval: 30 * time.Minute,
+			},
+		},
+		engine: newTestEngine(mockPlatforms),
+	}
+
+	result, err := job.checkPolicy(&platforms.Release{
+		Tag:       "v2.0.0",
+		CreatedAt: time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Errorf("error not expected: %#v", err)
+	}
+
+	if result.state != gatePending {
+		t.Errorf("Expected policy gate to be pending, got %s", result.state)
+	}
+
+	if result.recheckAfter != 30*time.Minute {
+		t.Errorf("Expected policy gate to be rechecked after 30m, got %s",
+			result.recheckAfter)
+	}
+
+	expected := []string{
+		"major releases require a 24h soak",
+		"rule previous not satisfied",
+	}
+	if diff := pretty.Compare(result.messages, expected); diff != "" {
+		t.Errorf("diff: (-got +want)\n%s", diff)
+	}
+}
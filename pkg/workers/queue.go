@@ -1,6 +1,9 @@
 package workers
 
 import (
+	"sync"
+	"time"
+
 	"github.com/rs/zerolog/log"
 )
 
@@ -17,34 +20,186 @@ type WorkerPool struct {
 
 	// Workers
 	Workers []*Worker
+
+	cancel chan struct{}
+
+	// mu protects the workers, size and started fields when the pool is
+	// resized
+	mu      sync.Mutex
+	size    int
+	started bool
+
+	// rechecks keep track of the repositories scheduled for a recheck, indexed
+	// by "owner/repository", to avoid scheduling the same repository twice
+	rechecks struct {
+		sync.Mutex
+		scheduled map[string]*recheck
+	}
+}
+
+// recheck is a job scheduled to be queued once the deadline is reached
+type recheck struct {
+	deadline time.Time
+	timer    *time.Timer
 }
 
 // NewWorkerPool return a WorkerPool to process jobs
 func NewWorkerPool(workerCount int, cancel chan struct{}) *WorkerPool {
-	workers := []*Worker{}
-	workerQueue := make(chan chan *Job, workerCount)
+	wp := &WorkerPool{
+		JobQueue:    make(chan *Job, jobQueueBuffer),
+		WorkerQueue: make(chan chan *Job, workerCount),
+		Workers:     []*Worker{},
+		cancel:      cancel,
+		size:        workerCount,
+	}
+	wp.rechecks.scheduled = make(map[string]*recheck)
 
 	for i := 0; i < workerCount; i++ {
-		log.Info().Msgf("Initialising worker %d", i+1)
-		worker := NewWorker(i+1, workerQueue, cancel)
-		workers = append(workers, worker)
+		wp.Workers = append(wp.Workers, wp.newWorker(i+1))
 	}
 
-	return &WorkerPool{
-		JobQueue:    make(chan *Job, jobQueueBuffer),
-		WorkerQueue: workerQueue,
-		Workers:     workers,
+	return wp
+}
+
+// newWorker returns a worker attached to the pool
+func (wp *WorkerPool) newWorker(id int) *Worker {
+	log.Info().Msgf("Initialising worker %d", id)
+	worker := NewWorker(id, wp.WorkerQueue, wp.cancel)
+	worker.Recheck = wp.scheduleRecheck
+	worker.Retire = wp.retire
+	return worker
+}
+
+// Resize change the number of workers of the pool. Missing workers are
+// started right away, extra workers are stopped once they completed their
+// current job so that in-flight jobs are not lost
+func (wp *WorkerPool) Resize(workerCount int) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if workerCount == wp.size {
+		return
+	}
+
+	log.Info().Msgf("Resizing worker pool from %d to %d worker(s)", wp.size,
+		workerCount)
+
+	wp.size = workerCount
+
+	running := make(map[int]bool)
+	for _, worker := range wp.Workers {
+		running[worker.ID] = true
+	}
+
+	for id := 1; id <= workerCount; id++ {
+		if running[id] {
+			continue
+		}
+		worker := wp.newWorker(id)
+		wp.Workers = append(wp.Workers, worker)
+		if wp.started {
+			log.Info().
+				Int("worker", worker.ID).
+				Msg("Starting worker")
+			worker.Start()
+		}
+	}
+}
+
+// retire returns true and remove the worker from the pool if the pool was
+// downsized below the worker ID
+func (wp *WorkerPool) retire(worker *Worker) bool {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if worker.ID <= wp.size {
+		return false
 	}
+
+	for i, w := range wp.Workers {
+		if w == worker {
+			wp.Workers = append(wp.Workers[:i], wp.Workers[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// scheduleRecheck queue a new job for the repository once the recheck delay
+// of the job expired. If a recheck is already scheduled, the earliest one is
+// kept. The repository config is reloaded so that config changes are taken
+// into account
+func (wp *WorkerPool) scheduleRecheck(job *Job) {
+	if job.engine == nil {
+		return
+	}
+
+	key := job.Owner + "/" + job.Repository
+	deadline := time.Now().Add(job.RecheckAfter)
+
+	wp.rechecks.Lock()
+	defer wp.rechecks.Unlock()
+
+	if scheduled, ok := wp.rechecks.scheduled[key]; ok {
+		if !deadline.Before(scheduled.deadline) {
+			return
+		}
+		scheduled.timer.Stop()
+	}
+
+	log.Debug().
+		Str("owner", job.Owner).
+		Str("repository", job.Repository).
+		Msgf("Scheduling recheck in %s", job.RecheckAfter)
+
+	r := &recheck{deadline: deadline}
+	wp.rechecks.scheduled[key] = r
+
+	r.timer = time.AfterFunc(job.RecheckAfter, func() {
+		wp.rechecks.Lock()
+		// the recheck was replaced by an earlier one
+		if wp.rechecks.scheduled[key] != r {
+			wp.rechecks.Unlock()
+			return
+		}
+		delete(wp.rechecks.scheduled, key)
+		wp.rechecks.Unlock()
+
+		// the config may have been reloaded since the job was scheduled
+		engine := job.engine
+		if !engine.IsRepositoryAllowed(job.Owner, job.Repository) {
+			return
+		}
+
+		newJob, err := engine.NewJob(job.Owner, job.Repository)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("owner", job.Owner).
+				Str("repository", job.Repository).
+				Msg("Couldn't create recheck job")
+			return
+		}
+
+		select {
+		case wp.JobQueue <- newJob:
+		case <-wp.cancel:
+		}
+	})
 }
 
 // Start worker pool and dispatch job from JobQueue to the worker queue
 func (wp *WorkerPool) Start() {
+	wp.mu.Lock()
+	wp.started = true
 	for _, worker := range wp.Workers {
 		log.Info().
 			Int("worker", worker.ID).
 			Msg("Starting worker")
 		worker.Start()
 	}
+	wp.mu.Unlock()
 
 	go func() {
 		for job := range wp.JobQueue {
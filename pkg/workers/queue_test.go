@@ -0,0 +1,130 @@
+//go:build unit
+
+package workers
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestWorkerPoolResize(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	workerIDs := func(wp *WorkerPool) (ids []int) {
+		wp.mu.Lock()
+		defer wp.mu.Unlock()
+		for _, worker := range wp.Workers {
+			ids = append(ids, worker.ID)
+		}
+		sort.Ints(ids)
+		return
+	}
+
+	cancel := make(chan struct{})
+	defer close(cancel)
+
+	wp := NewWorkerPool(2, cancel)
+
+	t.Run("should add workers when upsized", func(t *testing.T) {
+		wp.Resize(4)
+
+		if diff := pretty.Compare(workerIDs(wp), []int{1, 2, 3, 4}); diff != "" {
+			t.Errorf("diff: (-got +want)\n%s", diff)
+		}
+	})
+
+	t.Run("should retire extra workers once they are done when downsized",
+		func(t *testing.T) {
+			wp.Resize(1)
+
+			// workers are only retired when they request a new job
+			if diff := pretty.Compare(workerIDs(wp), []int{1, 2, 3, 4}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			expectedRetired := map[int]bool{1: false, 2: true, 3: true}
+			for _, worker := range append([]*Worker{}, wp.Workers[:3]...) {
+				if retired := worker.Retire(worker); retired != expectedRetired[worker.ID] {
+					t.Errorf("Expected worker %d retired to be %t, got %t",
+						worker.ID, expectedRetired[worker.ID], retired)
+				}
+			}
+
+			if diff := pretty.Compare(workerIDs(wp), []int{1, 4}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+
+	t.Run("should keep workers not yet retired when upsized again",
+		func(t *testing.T) {
+			wp.Resize(3)
+
+			if diff := pretty.Compare(workerIDs(wp), []int{1, 2, 3, 4}); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			worker := wp.Workers[1]
+			if worker.ID != 4 || !worker.Retire(worker) {
+				t.Errorf("Expected worker 4 to be retired")
+			}
+		})
+}
+
+func TestWorkerPoolScheduleRecheck(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should keep the earliest recheck of a repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+		mockPlatforms.EXPECT().ReadFile("owner", "repository", ".grgate.yaml", "").
+			Return(strings.NewReader("enabled: true"), nil).Times(1)
+
+		_, mainConfig, err := config.LoadGlobalConfig("", nil)
+		if err != nil {
+			t.Fatalf("Error not expected: %#v", err)
+		}
+		mainConfig.OrgConfig = nil
+
+		cancel := make(chan struct{})
+		defer close(cancel)
+
+		wp := NewWorkerPool(1, cancel)
+		engine := newEngine(mockPlatforms, mainConfig, zerolog.Nop())
+
+		for _, recheckAfter := range []time.Duration{time.Hour, 2 * time.Hour, time.Millisecond} {
+			wp.scheduleRecheck(&Job{
+				Owner:        "owner",
+				Repository:   "repository",
+				RecheckAfter: recheckAfter,
+				engine:       engine,
+			})
+		}
+
+		select {
+		case job := <-wp.JobQueue:
+			if job.Repository != "repository" {
+				t.Errorf("Expected a recheck of repository, got %s", job.Repository)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected the earliest recheck to be queued")
+		}
+
+		wp.rechecks.Lock()
+		defer wp.rechecks.Unlock()
+		if len(wp.rechecks.scheduled) > 0 {
+			t.Errorf("Expected no recheck to be scheduled, got %d",
+				len(wp.rechecks.scheduled))
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package workers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fikaworks/grgate/pkg/config"
+)
+
+// quotaSeedRetryInterval define how long to wait before listing the releases
+// of a repository again when seeding its quota counters failed
+const quotaSeedRetryInterval = 5 * time.Minute
+
+// quotaScope associate a quota to the key its publishes are counted under
+type quotaScope struct {
+	key   string
+	name  string
+	quota *config.Quota
+}
+
+// quotaScopes returns the quotas enforced for the job repository
+func (j *Job) quotaScopes() (scopes []*quotaScope) {
+	if j.Config.Quota != nil && j.Config.Quota.Limit > 0 {
+		scopes = append(scopes, &quotaScope{
+			key:   j.Owner + "/" + j.Repository,
+			name:  "repository",
+			quota: j.Config.Quota,
+		})
+	}
+
+	if mainConfig := j.mainConfig(); mainConfig.OrgQuota != nil &&
+		mainConfig.OrgQuota.Limit > 0 {
+		scopes = append(scopes, &quotaScope{
+			key:   j.Owner,
+			name:  "organization",
+			quota: mainConfig.OrgQuota,
+		})
+	}
+
+	return
+}
+
+// reservePublish check that publishing a release doesn't exceed the
+// repository and organization quotas. If a quota is exceeded, the reason and
+// how long to wait before a release can be published again are returned,
+// otherwise the publish is recorded when record is true
+func (j *Job) reservePublish(now time.Time, record bool) (reason string, wait time.Duration) {
+	scopes := j.quotaScopes()
+	if len(scopes) == 0 {
+		return
+	}
+
+	j.seedPublishes(scopes, now)
+
+	publishes := &j.engine.publishes
+	publishes.Lock()
+	defer publishes.Unlock()
+
+	for _, scope := range scopes {
+		var recent []time.Time
+		for _, t := range publishes.times[scope.key] {
+			if now.Sub(t) < scope.quota.Period {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) == 0 {
+			delete(publishes.times, scope.key)
+		} else {
+			publishes.times[scope.key] = recent
+		}
+
+		if len(recent) < scope.quota.Limit {
+			continue
+		}
+
+		// times are recorded in order, a slot is freed when the oldest publish
+		// counted toward the limit leave the period
+		freed := recent[len(recent)-scope.quota.Limit].Add(scope.quota.Period).Sub(now)
+		if wait == 0 || freed > wait {
+			wait = freed
+		}
+		if reason == "" {
+			reason = fmt.Sprintf("%s quota of %d release(s) per %s reached",
+				scope.name, scope.quota.Limit, scope.quota.Period)
+		}
+	}
+
+	if reason != "" || !record {
+		return
+	}
+
+	for _, scope := range scopes {
+		publishes.times[scope.key] = append(publishes.times[scope.key], now)
+	}
+
+	return "", 0
+}
+
+// seedPublishes record the publish time of the releases of the job repository
+// the first time the repository is processed by the engine, so that releases
+// published before GRGate started are counted toward the quotas. Releases are
+// listed without holding the publishes lock so that jobs of other repositories
+// are not blocked by the platform API. If listing the releases fails, seeding
+// is attempted again after quotaSeedRetryInterval
+func (j *Job) seedPublishes(scopes []*quotaScope, now time.Time) {
+	key := j.Owner + "/" + j.Repository
+	publishes := &j.engine.publishes
+
+	publishes.Lock()
+	_, seeded := publishes.seeded[key]
+	retry, failed := publishes.seedRetry[key]
+	publishes.Unlock()
+
+	if seeded || (failed && now.Before(retry)) {
+		return
+	}
+
+	releaseList, err := j.Platform.ListReleases(j.Owner, j.Repository)
+
+	publishes.Lock()
+	defer publishes.Unlock()
+
+	// another job of the same repository may have seeded the counters while
+	// the releases were listed
+	if _, ok := publishes.seeded[key]; ok {
+		return
+	}
+
+	if err != nil {
+		publishes.seedRetry[key] = now.Add(quotaSeedRetryInterval)
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Msgf("Couldn't list releases, quotas only count the releases published by GRGate, retrying in %s",
+				quotaSeedRetryInterval)
+		return
+	}
+	publishes.seeded[key] = struct{}{}
+	delete(publishes.seedRetry, key)
+
+	for _, scope := range scopes {
+		times := publishes.times[scope.key]
+		for _, release := range releaseList {
+			if release.Draft || release.PublishedAt.IsZero() ||
+				now.Sub(release.PublishedAt) >= scope.quota.Period {
+				continue
+			}
+			times = append(times, release.PublishedAt)
+		}
+		sort.Slice(times, func(a, b int) bool { return times[a].Before(times[b]) })
+		publishes.times[scope.key] = times
+	}
+}
+
+// cancelPublish remove a publish recorded by reservePublish, used when the
+// release couldn't be published
+func (j *Job) cancelPublish(now time.Time) {
+	publishes := &j.engine.publishes
+	publishes.Lock()
+	defer publishes.Unlock()
+
+	for _, scope := range j.quotaScopes() {
+		times := publishes.times[scope.key]
+		for i := len(times) - 1; i >= 0; i-- {
+			if times[i].Equal(now) {
+				publishes.times[scope.key] = append(times[:i], times[i+1:]...)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+//go:build unit
+
+package workers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestReservePublish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+	mockPlatforms.EXPECT().ListReleases("fikaworks", gomock.Any()).
+		Return([]*platforms.Release{}, nil).AnyTimes()
+
+	engine := newEngine(mockPlatforms, &config.MainConfig{
+		OrgQuota: &config.Quota{Limit: 3, Period: time.Hour},
+	}, zerolog.Nop())
+
+	newJob := func(repository string) *Job {
+		return &Job{
+			Platform:   mockPlatforms,
+			Owner:      "fikaworks",
+			Repository: repository,
+			Config: &config.RepoConfig{
+				Quota: &config.Quota{Limit: 2, Period: 24 * time.Hour},
+			},
+			engine: engine,
+		}
+	}
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should not record publishes in dry-run", func(t *testing.T) {
+		if reason, _ := newJob("other").reservePublish(now, false); reason != "" {
+			t.Errorf("Unexpected quota reached: %s", reason)
+		}
+		if _, ok := engine.publishes.times["fikaworks/other"]; ok {
+			t.Errorf("Expected publish not to be recorded")
+		}
+	})
+
+	t.Run("should record publishes within the quotas", func(t *testing.T) {
+		for i, repository := range []string{"backend", "backend", "frontend"} {
+			if reason, _ := newJob(repository).reservePublish(
+				now.Add(time.Duration(i)*time.Minute), true); reason != "" {
+				t.Errorf("Unexpected quota reached: %s", reason)
+			}
+		}
+	})
+
+	t.Run("should queue the release if the organization quota is reached",
+		func(t *testing.T) {
+			reason, wait := newJob("api").reservePublish(now.Add(30*time.Minute), true)
+			expected := "organization quota of 3 release(s) per 1h0m0s reached"
+			if reason != expected {
+				t.Errorf("Expected %s, got %s", expected, reason)
+			}
+			if wait != 30*time.Minute {
+				t.Errorf("Expected wait of 30m, got %s", wait)
+			}
+		})
+
+	t.Run("should free a slot once the publish is cancelled", func(t *testing.T) {
+		newJob("frontend").cancelPublish(now.Add(2 * time.Minute))
+		if reason, _ := newJob("api").reservePublish(now.Add(30*time.Minute), true); reason != "" {
+			t.Errorf("Unexpected quota reached: %s", reason)
+		}
+	})
+
+	t.Run("should queue the release if the repository quota is reached",
+		func(t *testing.T) {
+			reason, wait := newJob("backend").reservePublish(now.Add(2*time.Hour), true)
+			expected := "repository quota of 2 release(s) per 24h0m0s reached"
+			if reason != expected {
+				t.Errorf("Expected %s, got %s", expected, reason)
+			}
+			if wait != 22*time.Hour {
+				t.Errorf("Expected wait of 22h, got %s", wait)
+			}
+		})
+}
+
+func TestSeedPublishes(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+	mockPlatforms.EXPECT().ListReleases("fikaworks", "backend").
+		Return([]*platforms.Release{
+			{Tag: "v1.3.0", Draft: true},
+			{Tag: "v1.2.0", PublishedAt: now.Add(-10 * time.Minute)},
+			{Tag: "v1.1.0", PublishedAt: now.Add(-20 * time.Minute)},
+			{Tag: "v1.0.0", PublishedAt: now.Add(-48 * time.Hour)},
+		}, nil).Times(1)
+
+	job := &Job{
+		Platform:   mockPlatforms,
+		Owner:      "fikaworks",
+		Repository: "backend",
+		Config: &config.RepoConfig{
+			Quota: &config.Quota{Limit: 2, Period: time.Hour},
+		},
+		engine: newTestEngine(mockPlatforms),
+	}
+
+	t.Run("should count the releases published before the engine started",
+		func(t *testing.T) {
+			reason, wait := job.reservePublish(now, true)
+			expected := "repository quota of 2 release(s) per 1h0m0s reached"
+			if reason != expected {
+				t.Errorf("Expected %s, got %s", expected, reason)
+			}
+			if wait != 40*time.Minute {
+				t.Errorf("Expected wait of 40m, got %s", wait)
+			}
+		})
+
+	t.Run("should only list the releases of a repository once", func(t *testing.T) {
+		if reason, _ := job.reservePublish(now.Add(time.Hour), true); reason != "" {
+			t.Errorf("Unexpected quota reached: %s", reason)
+		}
+	})
+}
+
+func TestSeedPublishesRetry(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+	gomock.InOrder(
+		mockPlatforms.EXPECT().ListReleases("fikaworks", "backend").
+			Return(nil, errors.New("rate limited")).Times(1),
+		mockPlatforms.EXPECT().ListReleases("fikaworks", "backend").
+			Return([]*platforms.Release{
+				{Tag: "v1.0.0", PublishedAt: now.Add(-10 * time.Minute)},
+			}, nil).Times(1),
+	)
+
+	job := &Job{
+		Platform:   mockPlatforms,
+		Owner:      "fikaworks",
+		Repository: "backend",
+		Config: &config.RepoConfig{
+			Quota: &config.Quota{Limit: 2, Period: time.Hour},
+		},
+		engine: newTestEngine(mockPlatforms),
+	}
+
+	t.Run("should not list the releases again before the retry interval",
+		func(t *testing.T) {
+			job.reservePublish(now, false)
+			job.reservePublish(now.Add(time.Minute), false)
+		})
+
+	t.Run("should seed the counters once the retry interval elapsed",
+		func(t *testing.T) {
+			job.reservePublish(now.Add(quotaSeedRetryInterval), true)
+			reason, _ := job.reservePublish(now.Add(quotaSeedRetryInterval), false)
+			expected := "repository quota of 2 release(s) per 1h0m0s reached"
+			if reason != expected {
+				t.Errorf("Expected %s, got %s", expected, reason)
+			}
+		})
+}
@@ -0,0 +1,77 @@
+package workers
+
+import (
+	"strings"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// IsRepositoryAllowed returns true if the repository is allowed by the
+// repositories include/exclude lists of the main config. Skipped repositories
+// are logged and counted. The topics are only requested when a topic pattern
+// is defined, repositories are skipped if their topics can't be listed
+func (e *Engine) IsRepositoryAllowed(owner, repository string) bool {
+	mainConfig := e.Config()
+	if mainConfig.Repositories == nil {
+		return true
+	}
+
+	include := mainConfig.Repositories.Include
+	exclude := mainConfig.Repositories.Exclude
+
+	var topics []string
+	if hasTopicPattern(include) || hasTopicPattern(exclude) {
+		var err error
+		if topics, err = e.Platform().ListTopics(owner, repository); err != nil {
+			e.logger.Error().
+				Err(err).
+				Str("owner", owner).
+				Str("repository", repository).
+				Msg("Couldn't list repository topics, skipping repository")
+			e.skippedRepositories.WithLabelValues("error").Inc()
+			return false
+		}
+	}
+
+	if len(include) > 0 && !matchRepository(include, owner, repository, topics) {
+		e.logger.Info().
+			Str("owner", owner).
+			Str("repository", repository).
+			Msg("Repository is not included, skipping repository")
+		e.skippedRepositories.WithLabelValues("not_included").Inc()
+		return false
+	}
+
+	if matchRepository(exclude, owner, repository, topics) {
+		e.logger.Info().
+			Str("owner", owner).
+			Str("repository", repository).
+			Msg("Repository is excluded, skipping repository")
+		e.skippedRepositories.WithLabelValues("excluded").Inc()
+		return false
+	}
+
+	return true
+}
+
+// matchRepository returns true if the repository match one of the patterns
+func matchRepository(patterns []string, owner, repository string,
+	topics []string) bool {
+	for _, pattern := range patterns {
+		if utils.MatchRepository(pattern, owner, repository, topics) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTopicPattern returns true if one of the patterns match topics
+func hasTopicPattern(patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(strings.ToLower(pattern), config.RepositoryTopicPrefix) {
+			return true
+		}
+	}
+	return false
+}
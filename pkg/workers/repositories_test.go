@@ -0,0 +1,106 @@
+//go:build unit
+
+package workers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+func TestIsRepositoryAllowed(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	testCases := map[string]struct {
+		repositories *config.Repositories
+		repository   string
+		topics       []string
+		topicsErr    error
+		expected     bool
+		reason       string
+	}{
+		"should allow all repositories without lists": {
+			repository: "grgate",
+			expected:   true,
+		},
+		"should allow included repositories": {
+			repositories: &config.Repositories{Include: []string{"fikaworks/grgate*"}},
+			repository:   "grgate-example",
+			expected:     true,
+		},
+		"should skip repositories which are not included": {
+			repositories: &config.Repositories{Include: []string{"fikaworks/grgate*"}},
+			repository:   "website",
+			expected:     false,
+			reason:       "not_included",
+		},
+		"should skip excluded repositories": {
+			repositories: &config.Repositories{
+				Include: []string{"fikaworks/*"},
+				Exclude: []string{"fikaworks/website"},
+			},
+			repository: "website",
+			expected:   false,
+			reason:     "excluded",
+		},
+		"should match repository topics": {
+			repositories: &config.Repositories{Include: []string{"topic:release-gated"}},
+			repository:   "grgate",
+			topics:       []string{"release-gated"},
+			expected:     true,
+		},
+		"should skip repositories with excluded topics": {
+			repositories: &config.Repositories{Exclude: []string{"topic:archived"}},
+			repository:   "grgate",
+			topics:       []string{"archived"},
+			expected:     false,
+			reason:       "excluded",
+		},
+		"should skip repositories if topics can't be listed": {
+			repositories: &config.Repositories{Include: []string{"topic:release-gated"}},
+			repository:   "grgate",
+			topicsErr:    errors.New("not found"),
+			expected:     false,
+			reason:       "error",
+		},
+	}
+
+	for title, testCase := range testCases {
+		t.Run(title, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+			if testCase.topics != nil || testCase.topicsErr != nil {
+				mockPlatforms.EXPECT().ListTopics("fikaworks", testCase.repository).
+					Return(testCase.topics, testCase.topicsErr)
+			}
+
+			engine, err := NewEngine(Options{
+				Platform: mockPlatforms,
+				Config:   &config.MainConfig{Repositories: testCase.repositories},
+			})
+			if err != nil {
+				t.Fatalf("Error not expected: %#v", err)
+			}
+
+			result := engine.IsRepositoryAllowed("fikaworks", testCase.repository)
+			if result != testCase.expected {
+				t.Errorf("Expected %t, got %t", testCase.expected, result)
+			}
+
+			if testCase.reason != "" {
+				count := testutil.ToFloat64(engine.skippedRepositories.WithLabelValues(testCase.reason))
+				if count != 1 {
+					t.Errorf("Expected skipped repositories metric to be incremented")
+				}
+			}
+		})
+	}
+}
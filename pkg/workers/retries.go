@@ -0,0 +1,136 @@
+package workers
+
+import (
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// statusRetries returns the number of retries allowed for each required
+// status, indexed by status name
+func (j *Job) statusRetries() map[string]int {
+	retries := make(map[string]int)
+	for _, rule := range j.Config.StatusRules {
+		if rule.Retries > 0 {
+			retries[rule.Name] = rule.Retries
+		}
+	}
+	return retries
+}
+
+// processRetries retry the failed required statuses of a release which have a
+// retries policy defined. The number of attempts is recorded in the release
+// note so that a status is not retried more than the allowed number of times
+func (j *Job) processRetries(release *platforms.Release, statuses []string,
+	dashboard *utils.DashboardData) (err error) {
+	retries := j.statusRetries()
+	if len(retries) == 0 {
+		return
+	}
+
+	statusList, err := j.Platform.ListStatuses(j.Owner, j.Repository,
+		release.CommitSha)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't list release statuses")
+		return
+	}
+
+	attempts := utils.GetRetryAttempts(release.ReleaseNote)
+	updated := false
+
+	for _, name := range statuses {
+		if retries[name] == 0 {
+			continue
+		}
+
+		var status *platforms.Status
+		for _, s := range statusList {
+			if s.Name == name && s.IsFailed() {
+				status = s
+				break
+			}
+		}
+
+		if status == nil || attempts[name] >= retries[name] {
+			if attempts[name] > 0 {
+				dashboard.RetriedStatuses = append(dashboard.RetriedStatuses,
+					&utils.DashboardRetry{
+						Attempts: attempts[name],
+						Retries:  retries[name],
+						Status:   name,
+						Tag:      release.Tag,
+					})
+			}
+			continue
+		}
+
+		if !j.Config.Enabled {
+			j.logger().Info().
+				Str("repository", j.Repository).
+				Str("owner", j.Owner).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("Status %s failed, would retry it (attempt %d/%d) [dry-run]",
+					name, attempts[name]+1, retries[name])
+			continue
+		}
+
+		if err = j.Platform.RetryStatus(j.Owner, j.Repository, status); err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("Couldn't retry status %s", name)
+			continue
+		}
+
+		attempts[name]++
+		updated = true
+
+		j.logger().Info().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msgf("Status %s failed, retried it (attempt %d/%d)", name,
+				attempts[name], retries[name])
+
+		dashboard.RetriedStatuses = append(dashboard.RetriedStatuses,
+			&utils.DashboardRetry{
+				Attempts: attempts[name],
+				Retries:  retries[name],
+				Status:   name,
+				Tag:      release.Tag,
+			})
+	}
+
+	if !updated {
+		return nil
+	}
+
+	release.ReleaseNote = utils.SetRetryAttempts(release.ReleaseNote, attempts)
+	if err = j.Platform.UpdateRelease(j.Owner, j.Repository, release); err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't record retry attempts in release note")
+		return
+	}
+
+	return nil
+}
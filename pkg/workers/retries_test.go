@@ -0,0 +1,98 @@
+//go:build unit
+
+package workers
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+func TestProcessRetries(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should retry failed statuses until retries are exhausted",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().ListStatuses(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, _ string) ([]*platforms.Status, error) {
+					return []*platforms.Status{
+						{ID: int64(1), Name: "e2e happy flow", Status: "completed", State: "failure"},
+						{ID: int64(2), Name: "e2e user account", Status: "completed", State: "failure"},
+						{ID: int64(3), Name: "lint", Status: "completed", State: "failure"},
+					}, nil
+				})
+
+			mockPlatforms.EXPECT().RetryStatus(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, status *platforms.Status) error {
+					if status.Name != "e2e happy flow" {
+						t.Errorf("Unexpected retry of status %s", status.Name)
+					}
+					return nil
+				})
+
+			var releaseNote string
+			mockPlatforms.EXPECT().UpdateRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, release *platforms.Release) error {
+					releaseNote = release.ReleaseNote
+					return nil
+				})
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Enabled: true,
+					StatusRules: []*config.StatusRule{
+						{Name: "e2e happy flow", Retries: 2},
+						{Name: "e2e user account", Retries: 1},
+					},
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			release := &platforms.Release{
+				Tag: "v1.2.3",
+				ReleaseNote: utils.SetRetryAttempts("release note",
+					map[string]int{"e2e user account": 1}),
+			}
+
+			dashboard := &utils.DashboardData{}
+			err := job.processRetries(release,
+				[]string{"e2e happy flow", "e2e user account", "lint"}, dashboard)
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			expectedAttempts := map[string]int{
+				"e2e happy flow":   1,
+				"e2e user account": 1,
+			}
+			if diff := pretty.Compare(utils.GetRetryAttempts(releaseNote),
+				expectedAttempts); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+
+			expectedDashboard := []*utils.DashboardRetry{
+				{Attempts: 1, Retries: 2, Status: "e2e happy flow", Tag: "v1.2.3"},
+				{Attempts: 1, Retries: 1, Status: "e2e user account", Tag: "v1.2.3"},
+			}
+			if diff := pretty.Compare(dashboard.RetriedStatuses,
+				expectedDashboard); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
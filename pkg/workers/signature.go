@@ -0,0 +1,189 @@
+package workers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// signatureGateName is the name of the signature gate displayed in the
+// release note
+const signatureGateName = "grgate/signature"
+
+// checkSignature make sure that the release tag or commit has a signature
+// verified by the platform and, if configured, verify it locally against the
+// keyring or the allowed signers
+func (j *Job) checkSignature(release *platforms.Release) (result *gateResult, err error) {
+	result = &gateResult{
+		name:  signatureGateName,
+		state: gateSucceeded,
+	}
+
+	signature, err := j.Platform.GetSignature(j.Owner, j.Repository, release,
+		j.Config.Signature.Target)
+	if err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msgf("Couldn't get %s signature", j.Config.Signature.Target)
+		return nil, err
+	}
+
+	result.signature = signature
+
+	if !signature.Verified {
+		result.state = gateFailed
+		result.messages = append(result.messages,
+			fmt.Sprintf("%s signature is not verified (%s)",
+				j.Config.Signature.Target, signature.Reason))
+		return result, nil
+	}
+
+	if j.Config.Signature.Keyring != "" || j.Config.Signature.AllowedSigners != "" {
+		if message := j.verifySignatureLocally(signature); message != "" {
+			result.state = gateFailed
+			result.messages = append(result.messages, message)
+			return result, nil
+		}
+	}
+
+	// SSH signers are restricted by the allowed signers file, allowed key IDs
+	// can't be checked against an SSH signature
+	if len(j.Config.Signature.AllowedKeyIDs) > 0 &&
+		utils.IsSSHSignature(signature.Signature) &&
+		j.Config.Signature.AllowedSigners == "" {
+		result.state = gateFailed
+		result.messages = append(result.messages,
+			"SSH signature found but no allowed signers file is configured")
+	} else if len(j.Config.Signature.AllowedKeyIDs) > 0 &&
+		!utils.IsSSHSignature(signature.Signature) &&
+		!isAllowedKeyID(signature.KeyID, j.Config.Signature.AllowedKeyIDs) {
+		result.state = gateFailed
+		if signature.KeyID == "" {
+			result.messages = append(result.messages,
+				"couldn't determine the signing key, a keyring is required")
+		} else {
+			result.messages = append(result.messages,
+				fmt.Sprintf("signing key %s is not allowed", signature.KeyID))
+		}
+	}
+
+	j.logger().Debug().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("Signature gate: %s", result.state)
+
+	return result, nil
+}
+
+// verifySignatureLocally verify the signed payload against the configured
+// keyring (GPG) or allowed signers file (SSH). The key ID or principals of the
+// signer are stored in the signature. Returns a message if the verification
+// failed
+func (j *Job) verifySignatureLocally(signature *platforms.Signature) string {
+	if signature.Payload == "" || signature.Signature == "" {
+		return "local signature verification is not supported by the platform"
+	}
+
+	if utils.IsSSHSignature(signature.Signature) {
+		if j.Config.Signature.AllowedSigners == "" {
+			return "SSH signature found but no allowed signers file is configured"
+		}
+
+		allowedSignersPath, err := j.signatureFilePath(j.Config.Signature.AllowedSigners)
+		if err != nil {
+			return fmt.Sprintf("couldn't read allowed signers file: %s", err)
+		}
+
+		allowedSigners, err := os.Open(allowedSignersPath)
+		if err != nil {
+			return fmt.Sprintf("couldn't read allowed signers file: %s", err)
+		}
+		defer allowedSigners.Close()
+
+		principals, err := utils.VerifySSHSignature(allowedSigners,
+			signature.Payload, signature.Signature)
+		if err != nil {
+			return fmt.Sprintf("SSH signature verification failed: %s", err)
+		}
+
+		signature.Signer = principals
+		return ""
+	}
+
+	if j.Config.Signature.Keyring == "" {
+		return "GPG signature found but no keyring is configured"
+	}
+
+	keyringPath, err := j.signatureFilePath(j.Config.Signature.Keyring)
+	if err != nil {
+		return fmt.Sprintf("couldn't read keyring: %s", err)
+	}
+
+	keyring, err := os.Open(keyringPath)
+	if err != nil {
+		return fmt.Sprintf("couldn't read keyring: %s", err)
+	}
+	defer keyring.Close()
+
+	fingerprint, err := utils.VerifyGPGSignature(keyring, signature.Payload,
+		signature.Signature)
+	if err != nil {
+		return fmt.Sprintf("GPG signature verification failed: %s", err)
+	}
+
+	signature.KeyID = fingerprint
+	return ""
+}
+
+// signatureFilePath returns the path of a keyring or allowed signers file.
+// Files are resolved from the signatures directory only, absolute paths and
+// paths leaving the directory are rejected so that a repository config can't
+// read arbitrary files of the server
+func (j *Job) signatureFilePath(name string) (string, error) {
+	signaturesDir := j.mainConfig().SignaturesDir
+	if signaturesDir == "" {
+		return "", fmt.Errorf("signatures directory is undefined")
+	}
+
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid path \"%s\", must be relative to the signatures directory",
+			name)
+	}
+
+	return filepath.Join(signaturesDir, name), nil
+}
+
+// isAllowedKeyID returns true if one of the allowed key IDs is a suffix of the
+// ID of the signing key, which is either a long key ID or a fingerprint. Short
+// key IDs are ignored since colliding keys are easily generated, and a signing
+// key ID is never matched as a suffix of an allowed fingerprint
+func isAllowedKeyID(keyID string, allowedKeyIDs []string) bool {
+	if !config.IsValidKeyID(keyID) {
+		return false
+	}
+
+	keyID = strings.ToUpper(keyID)
+	for _, allowed := range allowedKeyIDs {
+		if !config.IsValidKeyID(allowed) {
+			continue
+		}
+		if strings.HasSuffix(keyID, strings.ToUpper(allowed)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,187 @@
+//go:build unit
+
+package workers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+)
+
+const (
+	testSSHSignature = "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n"
+	testGPGSignature = "-----BEGIN PGP SIGNATURE-----\nwsBc\n-----END PGP SIGNATURE-----\n"
+)
+
+func TestCheckSignature(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	signaturesDir := t.TempDir()
+
+	testCases := map[string]struct {
+		signature        *platforms.Signature
+		config           *config.Signature
+		expectedState    string
+		expectedMessages []string
+	}{
+		"should fail signatures which are not verified": {
+			signature:     &platforms.Signature{Reason: "unsigned"},
+			config:        &config.Signature{},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"tag signature is not verified (unsigned)",
+			},
+		},
+		"should succeed GPG signatures of allowed keys": {
+			signature: &platforms.Signature{Verified: true, KeyID: "4AEE18F83AFDEB23"},
+			config: &config.Signature{
+				AllowedKeyIDs: []string{"4aee18f83afdeb23"},
+			},
+			expectedState: gateSucceeded,
+		},
+		"should match allowed long key IDs against the signing key fingerprint": {
+			signature: &platforms.Signature{
+				Verified: true,
+				KeyID:    "0D69E11F12BDBA077B3726AB4AEE18F83AFDEB23",
+			},
+			config: &config.Signature{
+				AllowedKeyIDs: []string{"4AEE18F83AFDEB23"},
+			},
+			expectedState: gateSucceeded,
+		},
+		"should not match allowed short key IDs": {
+			signature: &platforms.Signature{Verified: true, KeyID: "4AEE18F83AFDEB23"},
+			config: &config.Signature{
+				AllowedKeyIDs: []string{"3AFDEB23"},
+			},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"signing key 4AEE18F83AFDEB23 is not allowed",
+			},
+		},
+		"should not match the signing key ID against an allowed fingerprint": {
+			signature: &platforms.Signature{Verified: true, KeyID: "4AEE18F83AFDEB23"},
+			config: &config.Signature{
+				AllowedKeyIDs: []string{"0D69E11F12BDBA077B3726AB4AEE18F83AFDEB23"},
+			},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"signing key 4AEE18F83AFDEB23 is not allowed",
+			},
+		},
+		"should fail GPG signatures of keys which are not allowed": {
+			signature: &platforms.Signature{Verified: true, KeyID: "4AEE18F83AFDEB23"},
+			config: &config.Signature{
+				AllowedKeyIDs: []string{"1111222233334444"},
+			},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"signing key 4AEE18F83AFDEB23 is not allowed",
+			},
+		},
+		"should fail SSH signatures when only allowed key IDs are defined": {
+			signature: &platforms.Signature{
+				Verified:  true,
+				Payload:   "tree",
+				Signature: testSSHSignature,
+			},
+			config: &config.Signature{
+				AllowedKeyIDs: []string{"4AEE18F83AFDEB23"},
+			},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"SSH signature found but no allowed signers file is configured",
+			},
+		},
+		"should not read keyrings outside the signatures directory": {
+			signature: &platforms.Signature{
+				Verified:  true,
+				Payload:   "tree",
+				Signature: testGPGSignature,
+			},
+			config: &config.Signature{
+				Keyring: "../../etc/passwd",
+			},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"couldn't read keyring: invalid path \"../../etc/passwd\", must be relative to the signatures directory",
+			},
+		},
+		"should not read absolute allowed signers files": {
+			signature: &platforms.Signature{
+				Verified:  true,
+				Payload:   "tree",
+				Signature: testSSHSignature,
+			},
+			config: &config.Signature{
+				AllowedSigners: "/etc/passwd",
+			},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"couldn't read allowed signers file: invalid path \"/etc/passwd\", must be relative to the signatures directory",
+			},
+		},
+		"should read allowed signers files from the signatures directory": {
+			signature: &platforms.Signature{
+				Verified:  true,
+				Payload:   "tree",
+				Signature: testSSHSignature,
+			},
+			config: &config.Signature{
+				AllowedSigners: "allowed_signers",
+			},
+			expectedState: gateFailed,
+			expectedMessages: []string{
+				"couldn't read allowed signers file: open " +
+					filepath.Join(signaturesDir, "allowed_signers") +
+					": no such file or directory",
+			},
+		},
+	}
+
+	for title, testCase := range testCases {
+		t.Run(title, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+			mockPlatforms.EXPECT().GetSignature("owner", "repository", gomock.Any(), "tag").
+				Return(testCase.signature, nil)
+
+			testCase.config.Enabled = true
+			testCase.config.Target = "tag"
+
+			job := &Job{
+				Platform:   mockPlatforms,
+				Owner:      "owner",
+				Repository: "repository",
+				Config: &config.RepoConfig{
+					Signature: testCase.config,
+				},
+				engine: newEngine(mockPlatforms, &config.MainConfig{
+					SignaturesDir: signaturesDir,
+				}, zerolog.Nop()),
+			}
+
+			result, err := job.checkSignature(&platforms.Release{Tag: "v1.2.3"})
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			if result.state != testCase.expectedState {
+				t.Errorf("Expected signature gate to be %s, got %s",
+					testCase.expectedState, result.state)
+			}
+			if diff := pretty.Compare(result.messages, testCase.expectedMessages); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
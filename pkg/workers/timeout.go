@@ -0,0 +1,186 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// isTimeoutEnabled returns true if a timeout is defined in config
+func (j *Job) isTimeoutEnabled() bool {
+	return j.Config.Timeout != nil && j.Config.Timeout.Duration > 0
+}
+
+// processTimedOutRelease handle a draft release previously blocked by the
+// timeout, the release is deleted once the retention period is over. Releases
+// reverted to draft by a rollback are never deleted. Returns true if the
+// release has been blocked by the timeout and shouldn't be evaluated again
+func (j *Job) processTimedOutRelease(release *platforms.Release,
+	dashboard *utils.DashboardData) (blocked bool, err error) {
+	reason := utils.GetTimeoutReason(release.ReleaseNote)
+	if reason == "" {
+		return false, nil
+	}
+
+	dashboard.TimedOutReleases = append(dashboard.TimedOutReleases,
+		&utils.DashboardRelease{
+			Name:   release.Name,
+			Reason: reason,
+			Tag:    release.Tag,
+		})
+
+	start := utils.GetTimeoutStart(release.ReleaseNote)
+	if !j.isTimeoutEnabled() || j.Config.Timeout.Retention <= 0 || start.IsZero() ||
+		utils.GetRollbackReason(release.ReleaseNote) != "" ||
+		time.Since(start) < j.Config.Timeout.Duration+j.Config.Timeout.Retention {
+		return true, nil
+	}
+
+	if !j.Config.Enabled {
+		j.logger().Info().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Retention period is over, would delete release [dry-run]")
+		return true, nil
+	}
+
+	if err = j.Platform.DeleteRelease(j.Owner, j.Repository, release); err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't delete release")
+		return true, err
+	}
+
+	j.logger().Info().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msg("Retention period is over, release deleted")
+
+	return true, nil
+}
+
+// processTimeout mark a draft release as blocked if its required statuses
+// didn't succeed within the timeout. The timeout starts the first time the
+// release is processed, the start is recorded in the release note. The reason
+// is recorded in the release note and a notification is sent if a
+// notification URL is defined
+func (j *Job) processTimeout(release *platforms.Release,
+	dashboard *utils.DashboardData) (err error) {
+	if !j.isTimeoutEnabled() {
+		return
+	}
+
+	start := utils.GetTimeoutStart(release.ReleaseNote)
+	if start.IsZero() {
+		return j.startTimeout(release)
+	}
+
+	if time.Since(start) < j.Config.Timeout.Duration {
+		return
+	}
+
+	reason := fmt.Sprintf("required statuses didn't succeed within %s",
+		j.Config.Timeout.Duration)
+
+	dashboard.TimedOutReleases = append(dashboard.TimedOutReleases,
+		&utils.DashboardRelease{
+			Name:   release.Name,
+			Reason: reason,
+			Tag:    release.Tag,
+		})
+
+	if !j.Config.Enabled {
+		j.logger().Info().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msgf("%s, would mark release as blocked [dry-run]", reason)
+		return
+	}
+
+	release.ReleaseNote = utils.SetTimeoutReason(release.ReleaseNote, reason)
+	if err = j.Platform.UpdateRelease(j.Owner, j.Repository, release); err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't mark release as blocked")
+		return
+	}
+
+	j.logger().Info().
+		Str("repository", j.Repository).
+		Str("owner", j.Owner).
+		Str("releaseCommit", release.CommitSha).
+		Str("releaseTag", release.Tag).
+		Str("releaseName", release.Name).
+		Msgf("%s, release marked as blocked", reason)
+
+	if j.Config.Timeout.NotificationURL == "" {
+		return nil
+	}
+
+	if err := utils.SendNotification(j.Config.Timeout.NotificationURL,
+		&utils.Notification{
+			Owner:      j.Owner,
+			Repository: j.Repository,
+			Tag:        release.Tag,
+			Name:       release.Name,
+			Reason:     reason,
+			Text: fmt.Sprintf("GRGate blocked release %s of %s/%s: %s",
+				release.Tag, j.Owner, j.Repository, reason),
+		}); err != nil {
+		// the release is already marked as blocked, the notification is not
+		// sent again
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't send timeout notification")
+	}
+
+	return nil
+}
+
+// startTimeout record in the release note that the timeout of the release
+// starts now, nothing is recorded in dry-run
+func (j *Job) startTimeout(release *platforms.Release) (err error) {
+	if !j.Config.Enabled {
+		return
+	}
+
+	release.ReleaseNote = utils.SetTimeoutStart(release.ReleaseNote, time.Now())
+	if err = j.Platform.UpdateRelease(j.Owner, j.Repository, release); err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't record release timeout start")
+	}
+
+	return
+}
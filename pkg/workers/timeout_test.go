@@ -0,0 +1,195 @@
+//go:build unit
+
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+func TestProcessTimeout(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	timeout := &config.Timeout{
+		Duration:  72 * time.Hour,
+		Retention: 24 * time.Hour,
+	}
+
+	t.Run("should mark release as blocked when the timeout is exceeded",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().UpdateRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, release *platforms.Release) error {
+					if utils.GetTimeoutReason(release.ReleaseNote) == "" {
+						t.Errorf("Expected timeout notice in release note")
+					}
+					return nil
+				})
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Enabled: true,
+					Timeout: timeout,
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			dashboard := &utils.DashboardData{}
+			release := &platforms.Release{
+				Tag:         "v1.2.3",
+				CreatedAt:   time.Now().Add(-1 * time.Hour),
+				ReleaseNote: utils.SetTimeoutStart("", time.Now().Add(-73*time.Hour)),
+			}
+
+			if err := job.processTimeout(release, dashboard); err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			if len(dashboard.TimedOutReleases) != 1 {
+				t.Errorf("Expected 1 timed out release, got %d",
+					len(dashboard.TimedOutReleases))
+			}
+		})
+
+	t.Run("should not mark release as blocked within the timeout",
+		func(t *testing.T) {
+			job := &Job{
+				Config: &config.RepoConfig{
+					Enabled: true,
+					Timeout: timeout,
+				},
+				engine: newTestEngine(nil),
+			}
+
+			dashboard := &utils.DashboardData{}
+			release := &platforms.Release{
+				Tag:         "v1.2.3",
+				CreatedAt:   time.Now().Add(-73 * time.Hour),
+				ReleaseNote: utils.SetTimeoutStart("", time.Now().Add(-1*time.Hour)),
+			}
+
+			if err := job.processTimeout(release, dashboard); err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			if len(dashboard.TimedOutReleases) != 0 {
+				t.Errorf("Expected no timed out release")
+			}
+		})
+
+	t.Run("should record the timeout start of new releases",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().UpdateRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, release *platforms.Release) error {
+					if utils.GetTimeoutStart(release.ReleaseNote).IsZero() {
+						t.Errorf("Expected timeout start in release note")
+					}
+					return nil
+				})
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Enabled: true,
+					Timeout: timeout,
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			dashboard := &utils.DashboardData{}
+			release := &platforms.Release{
+				Tag:       "v1.2.3",
+				CreatedAt: time.Now().Add(-73 * time.Hour),
+			}
+
+			if err := job.processTimeout(release, dashboard); err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			if len(dashboard.TimedOutReleases) != 0 {
+				t.Errorf("Expected no timed out release")
+			}
+		})
+
+	t.Run("should delete blocked release after the retention period",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().DeleteRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(nil)
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Enabled: true,
+					Timeout: timeout,
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			dashboard := &utils.DashboardData{}
+			release := &platforms.Release{
+				Tag: "v1.2.3",
+				ReleaseNote: utils.SetTimeoutStart(utils.SetTimeoutReason("", "timeout"),
+					time.Now().Add(-97*time.Hour)),
+			}
+
+			blocked, err := job.processTimedOutRelease(release, dashboard)
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+			if !blocked {
+				t.Errorf("Expected release to be blocked")
+			}
+		})
+
+	t.Run("should not delete blocked release which has been rolled back",
+		func(t *testing.T) {
+			job := &Job{
+				Config: &config.RepoConfig{
+					Enabled: true,
+					Timeout: timeout,
+				},
+				engine: newTestEngine(nil),
+			}
+
+			dashboard := &utils.DashboardData{}
+			releaseNote := utils.SetRollbackReason("", "e2e failed after publication")
+			releaseNote = utils.SetTimeoutReason(releaseNote, "timeout")
+			release := &platforms.Release{
+				Tag:         "v1.2.3",
+				ReleaseNote: utils.SetTimeoutStart(releaseNote, time.Now().Add(-97*time.Hour)),
+			}
+
+			blocked, err := job.processTimedOutRelease(release, dashboard)
+			if err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+			if !blocked {
+				t.Errorf("Expected release to be blocked")
+			}
+		})
+}
@@ -0,0 +1,133 @@
+package workers
+
+import (
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+// processTriggers dispatch the workflows defined in config for a draft release.
+// Triggered workflows are recorded in the release note so that each workflow is
+// only dispatched once per release
+func (j *Job) processTriggers(release *platforms.Release) (err error) {
+	if len(j.Config.Triggers) == 0 {
+		return
+	}
+
+	triggered := utils.GetTriggeredWorkflows(release.ReleaseNote)
+	updated := false
+
+	for _, trigger := range j.Config.Triggers {
+		if isTriggered(trigger.Name, triggered) {
+			continue
+		}
+
+		if !j.Config.Enabled {
+			j.logger().Info().
+				Str("repository", j.Repository).
+				Str("owner", j.Owner).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("Would trigger workflow %s [dry-run]", trigger.Name)
+			continue
+		}
+
+		workflow, err := j.renderWorkflow(release, trigger)
+		if err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("Couldn't render workflow %s", trigger.Name)
+			continue
+		}
+
+		if err = j.Platform.TriggerWorkflow(j.Owner, j.Repository, release,
+			workflow); err != nil {
+			j.logger().Error().
+				Err(err).
+				Str("owner", j.Owner).
+				Str("repository", j.Repository).
+				Str("releaseCommit", release.CommitSha).
+				Str("releaseTag", release.Tag).
+				Str("releaseName", release.Name).
+				Msgf("Couldn't trigger workflow %s", trigger.Name)
+			continue
+		}
+
+		j.logger().Info().
+			Str("repository", j.Repository).
+			Str("owner", j.Owner).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msgf("Triggered workflow %s", trigger.Name)
+
+		triggered = append(triggered, trigger.Name)
+		updated = true
+	}
+
+	if !updated {
+		return nil
+	}
+
+	release.ReleaseNote = utils.SetTriggeredWorkflows(release.ReleaseNote, triggered)
+	if err = j.Platform.UpdateRelease(j.Owner, j.Repository, release); err != nil {
+		j.logger().Error().
+			Err(err).
+			Str("owner", j.Owner).
+			Str("repository", j.Repository).
+			Str("releaseCommit", release.CommitSha).
+			Str("releaseTag", release.Tag).
+			Str("releaseName", release.Name).
+			Msg("Couldn't record triggered workflows in release note")
+		return
+	}
+
+	return nil
+}
+
+// renderWorkflow returns the workflow to dispatch with its ref and inputs
+// rendered with the release data
+func (j *Job) renderWorkflow(release *platforms.Release,
+	trigger *config.Trigger) (workflow *platforms.Workflow, err error) {
+	data := &utils.TemplateData{
+		CommitSha:  release.CommitSha,
+		Name:       release.Name,
+		Owner:      j.Owner,
+		Repository: j.Repository,
+		Tag:        release.Tag,
+	}
+
+	workflow = &platforms.Workflow{
+		Name:   trigger.Name,
+		Inputs: make(map[string]string, len(trigger.Inputs)),
+	}
+
+	if workflow.Ref, err = utils.RenderTemplate(trigger.Ref, data); err != nil {
+		return nil, err
+	}
+
+	for _, input := range trigger.Inputs {
+		if workflow.Inputs[input.Name], err = utils.RenderTemplate(input.Value,
+			data); err != nil {
+			return nil, err
+		}
+	}
+
+	return workflow, nil
+}
+
+// isTriggered returns true if the workflow has already been triggered
+func isTriggered(name string, triggered []string) bool {
+	for _, t := range triggered {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+//go:build unit
+
+package workers
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/rs/zerolog"
+
+	"github.com/fikaworks/grgate/pkg/config"
+	"github.com/fikaworks/grgate/pkg/platforms"
+	mock_platforms "github.com/fikaworks/grgate/pkg/platforms/mocks"
+	"github.com/fikaworks/grgate/pkg/utils"
+)
+
+func TestProcessTriggers(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	t.Run("should only trigger workflows which have not been triggered yet",
+		func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPlatforms := mock_platforms.NewMockPlatform(ctrl)
+
+			mockPlatforms.EXPECT().TriggerWorkflow(gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ string, _ *platforms.Release, workflow *platforms.Workflow) error {
+					expected := &platforms.Workflow{
+						Name: "e2e.yaml",
+						Ref:  "main",
+						Inputs: map[string]string{
+							"E2E_FLOW": "happy-flow",
+							"tag":      "v1.2.3",
+						},
+					}
+					if diff := pretty.Compare(workflow, expected); diff != "" {
+						t.Errorf("diff: (-got +want)\n%s", diff)
+					}
+					return nil
+				})
+
+			var releaseNote string
+			mockPlatforms.EXPECT().UpdateRelease(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(_ string, _ string, release *platforms.Release) error {
+					releaseNote = release.ReleaseNote
+					return nil
+				})
+
+			job := &Job{
+				Platform: mockPlatforms,
+				Config: &config.RepoConfig{
+					Enabled: true,
+					Triggers: []*config.Trigger{
+						{
+							Name: "e2e.yaml",
+							Ref:  "main",
+							Inputs: []*config.TriggerInput{
+								{Name: "E2E_FLOW", Value: "happy-flow"},
+								{Name: "tag", Value: "{{ .Tag }}"},
+							},
+						},
+						{
+							Name: "smoke.yaml",
+						},
+					},
+				},
+				engine: newTestEngine(mockPlatforms),
+			}
+
+			release := &platforms.Release{
+				Tag: "v1.2.3",
+				ReleaseNote: utils.SetTriggeredWorkflows("release note",
+					[]string{"smoke.yaml"}),
+			}
+
+			if err := job.processTriggers(release); err != nil {
+				t.Errorf("error not expected: %#v", err)
+			}
+
+			expected := []string{"smoke.yaml", "e2e.yaml"}
+			if diff := pretty.Compare(utils.GetTriggeredWorkflows(releaseNote),
+				expected); diff != "" {
+				t.Errorf("diff: (-got +want)\n%s", diff)
+			}
+		})
+}
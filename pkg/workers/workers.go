@@ -10,6 +10,14 @@ type Worker struct {
 	Job    chan *Job
 	Queue  chan chan *Job
 	Cancel chan struct{}
+
+	// Recheck is called with jobs which have a pending gate requiring a
+	// recheck, if nil the job is not re-checked
+	Recheck func(*Job)
+
+	// Retire is called before waiting for a new job, the worker stops if it
+	// returns true. If nil the worker runs until cancelled
+	Retire func(*Worker) bool
 }
 
 // NewWorker return a worker which process jobs from a queue
@@ -26,7 +34,21 @@ func NewWorker(id int, queue chan chan *Job, cancel chan struct{}) *Worker {
 func (w *Worker) Start() {
 	go func() {
 		for {
-			w.Queue <- w.Job
+			if w.Retire != nil && w.Retire(w) {
+				log.Info().
+					Int("worker", w.ID).
+					Msg("Stopping worker, the worker pool was downsized")
+				return
+			}
+
+			select {
+			case w.Queue <- w.Job:
+			case <-w.Cancel:
+				log.Info().
+					Int("worker", w.ID).
+					Msg("Stopping worker queue")
+				return
+			}
 
 			select {
 			case work := <-w.Job:
@@ -35,7 +57,11 @@ func (w *Worker) Start() {
 					Str("owner", work.Owner).
 					Str("repository", work.Repository).
 					Msg("Processing work item from queue")
-				if err := work.Process(); err != nil {
+				err := work.Process()
+				if work.RecheckAfter > 0 && w.Recheck != nil {
+					w.Recheck(work)
+				}
+				if err != nil {
 					continue
 				}
 				log.Debug().
@@ -17,17 +17,28 @@ import (
 const repositoryPrefix = "grgate-integration"
 
 func runTests(t *testing.T, platform platforms.Platform, owner, author string) {
-	if _, err := config.NewGlobalConfig(""); err != nil {
+	_, mainConfig, err := config.LoadGlobalConfig("", nil)
+	if err != nil {
 		t.Errorf("Error not expected: %#v", err)
+		return
 	}
 
 	// force set author in order to look for issues by author during validation steps
-	config.Main.Globals.Dashboard.Author = author
+	mainConfig.Globals.Dashboard.Author = author
 
-	runTest(t, platform, owner, disabledConfigTestCases)
-	runTest(t, platform, owner, commitStatusTestCases)
-	runTest(t, platform, owner, releaseNoteTestCases)
-	runTest(t, platform, owner, dashboardTestCases)
+	engine, err := workers.NewEngine(workers.Options{
+		Platform: platform,
+		Config:   mainConfig,
+	})
+	if err != nil {
+		t.Errorf("Error not expected: %#v", err)
+		return
+	}
+
+	runTest(t, engine, owner, disabledConfigTestCases)
+	runTest(t, engine, owner, commitStatusTestCases)
+	runTest(t, engine, owner, releaseNoteTestCases)
+	runTest(t, engine, owner, dashboardTestCases)
 }
 
 func setup(platform platforms.Platform, owner string) (repository string, err error) {
@@ -43,7 +54,9 @@ func tearDown(platform platforms.Platform, owner, repository string) {
 }
 
 // runTests prepare a repository and run GRGate against it
-func runTest(t *testing.T, platform platforms.Platform, owner string, testCases map[string]*testCase) {
+func runTest(t *testing.T, engine *workers.Engine, owner string, testCases map[string]*testCase) {
+	platform := engine.Platform()
+
 	for title, testCase := range testCases {
 		t.Run(title, func(t *testing.T) {
 			repository, err := setup(platform, owner)
@@ -79,7 +92,7 @@ func runTest(t *testing.T, platform platforms.Platform, owner string, testCases
 			// fix flakky CreateRelease, it seems to have inconsistent delay
 			time.Sleep(time.Second)
 
-			job, err := workers.NewJob(platform, owner, repository)
+			job, err := engine.NewJob(owner, repository)
 			if err != nil {
 				t.Errorf("Couldn't create job: %#v", err)
 				return
@@ -106,7 +119,7 @@ func runTest(t *testing.T, platform platforms.Platform, owner string, testCases
 			time.Sleep(time.Second)
 
 			// validate issue dashboard
-			issueList, err := platform.ListIssuesByAuthor(owner, repository, config.Main.Globals.Dashboard.Author)
+			issueList, err := platform.ListIssuesByAuthor(owner, repository, engine.Config().Globals.Dashboard.Author)
 			if err != nil {
 				t.Errorf("Couldn't list issues from repository: %#v", err)
 				return
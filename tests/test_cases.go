@@ -230,12 +230,12 @@ statuses:
 - e2e happy flow`,
 			withTag:                  "v1.2.3",
 			expectIssueToBeCreated:   true,
-			expectErrorDuringProcess: true,
+			expectErrorDuringProcess: false,
 			expectedDashboardTitle:   "GRGate dashboard",
-			expectedDashboardBody: `GRGate is enabled for this repository.
-
-Incorrect configuration detected with the following error(s):
-- Couldn't compile regexp "[["`,
+			expectedDashboardBody: "GRGate is enabled for this repository.\n\n" +
+				"Incorrect configuration detected with the following error(s):\n" +
+				"- .grgate.yaml: line 2: tagRegexp: invalid regexp: error parsing regexp: " +
+				"missing closing ]: `[[`",
 		},
 	}
 )